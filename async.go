@@ -0,0 +1,87 @@
+package tlog
+
+import (
+	"sync"
+	"time"
+
+	"github.com/tarantool/go-tlog/internal/outputs"
+)
+
+// Overflow is a policy of asynchronous logging with a full queue.
+type Overflow int
+
+const (
+	// OverflowBlock makes the logging goroutine wait for a free slot.
+	OverflowBlock Overflow = iota
+	// OverflowDropNewest discards the record being logged.
+	OverflowDropNewest
+	// OverflowDropOldest discards the oldest queued record.
+	OverflowDropOldest
+)
+
+// Async configures asynchronous logging. Records are queued and written
+// to each output by a background goroutine, so a slow output does not
+// stall the logging goroutines. The zero value disables it.
+type Async struct {
+	// QueueSize is the maximum number of records waiting to be written
+	// to an output. Zero disables asynchronous logging.
+	QueueSize int
+	// Overflow is the policy applied when a queue is full.
+	Overflow Overflow
+	// DropReportInterval is how often the number of records dropped due
+	// to Overflow, a full Network.Backlog or Opts.NonBlock is logged.
+	// Default is 10 seconds.
+	DropReportInterval time.Duration
+}
+
+const defaultDropReportInterval = 10 * time.Second
+
+func (a Async) overflow() outputs.Overflow {
+	switch a.Overflow {
+	case OverflowDropNewest:
+		return outputs.OverflowDropNewest
+	case OverflowDropOldest:
+		return outputs.OverflowDropOldest
+	default:
+		return outputs.OverflowBlock
+	}
+}
+
+// reportDropped periodically logs the number of records dropped since
+// the previous report. The returned function stops reporting.
+func (l *Logger) reportDropped(interval time.Duration) (stop func()) {
+	if interval <= 0 {
+		interval = defaultDropReportInterval
+	}
+
+	ticker := time.NewTicker(interval)
+	done := make(chan struct{})
+
+	var wg sync.WaitGroup
+
+	wg.Add(1)
+
+	go func() {
+		defer wg.Done()
+		defer ticker.Stop()
+
+		var reported uint64
+
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				if dropped := l.outputs.Dropped(); dropped > reported {
+					l.logger.Warn("log records dropped", "count", dropped-reported)
+					reported = dropped
+				}
+			}
+		}
+	}()
+
+	return func() {
+		close(done)
+		wg.Wait()
+	}
+}
@@ -0,0 +1,136 @@
+package tlog_test
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/tarantool/go-tlog"
+)
+
+func Test_Logger_Async_CloseDrains(t *testing.T) {
+	t.Parallel()
+
+	r := require.New(t)
+
+	path := filepath.Join(t.TempDir(), "app.log")
+
+	l, err := tlog.New(tlog.Opts{
+		Path:  path,
+		Async: tlog.Async{QueueSize: 8},
+	})
+	r.NoError(err)
+
+	for i := range 500 {
+		l.Logger().Info("queued", "i", i)
+	}
+
+	r.NoError(l.Close())
+
+	logs, err := os.ReadFile(path)
+	r.NoError(err)
+	r.Equal(500, strings.Count(string(logs), "queued"))
+}
+
+// Not parallel: replaces os.Stderr.
+func Test_Logger_Async_DropReport(t *testing.T) {
+	r := require.New(t)
+
+	// Nobody reads from the pipe until the end of the test,
+	// so stderr output gets stuck once the pipe buffer is full.
+	pr, pw, err := os.Pipe()
+	r.NoError(err)
+
+	orig := os.Stderr
+	os.Stderr = pw
+
+	defer func() {
+		os.Stderr = orig
+	}()
+
+	path := filepath.Join(t.TempDir(), "app.log")
+
+	l, err := tlog.New(tlog.Opts{
+		Path: "stderr," + path,
+		Async: tlog.Async{
+			QueueSize:          1,
+			Overflow:           tlog.OverflowDropNewest,
+			DropReportInterval: 10 * time.Millisecond,
+		},
+	})
+	r.NoError(err)
+
+	payload := strings.Repeat("x", 16<<10)
+	for range 64 {
+		l.Logger().Info("large record", "payload", payload)
+	}
+
+	r.Eventually(func() bool {
+		logs, err := os.ReadFile(path)
+		return err == nil && strings.Contains(string(logs), "log records dropped")
+	}, 5*time.Second, 10*time.Millisecond)
+
+	go func() {
+		_, _ = io.Copy(io.Discard, pr)
+	}()
+
+	r.NoError(l.Close())
+	r.NoError(pw.Close())
+}
+
+// Not parallel: replaces os.Stderr.
+func Test_Logger_NonBlock(t *testing.T) {
+	r := require.New(t)
+
+	// Nobody reads from the pipe until the end of the test.
+	pr, pw, err := os.Pipe()
+	r.NoError(err)
+
+	orig := os.Stderr
+	os.Stderr = pw
+
+	defer func() {
+		os.Stderr = orig
+	}()
+
+	path := filepath.Join(t.TempDir(), "app.log")
+
+	l, err := tlog.New(tlog.Opts{
+		Path:     "stderr," + path,
+		NonBlock: true,
+		Async:    tlog.Async{DropReportInterval: 10 * time.Millisecond},
+	})
+	r.NoError(err)
+
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+
+		payload := strings.Repeat("x", 1<<10)
+		for range 256 {
+			l.Logger().Info("large record", "payload", payload)
+		}
+	}()
+
+	// Logging would block forever, as nobody reads from the pipe.
+	select {
+	case <-done:
+	case <-time.After(10 * time.Second):
+		r.FailNow("logging has blocked")
+	}
+
+	r.Eventually(func() bool {
+		logs, err := os.ReadFile(path)
+		return err == nil && strings.Contains(string(logs), "log records dropped")
+	}, 5*time.Second, 10*time.Millisecond)
+
+	r.NoError(l.Close())
+	r.NoError(pw.Close())
+	r.NoError(pr.Close())
+}
@@ -0,0 +1,53 @@
+package tlog
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/tarantool/go-tlog/internal/stacktrace"
+)
+
+// AddCallerSkip returns a logger which attributes records to the caller
+// skip frames above the function calling its logging methods, both in
+// the source and in the stacktrace. It is meant for wrappers of loggers,
+// e.g. a helper logging a request is skipped with 1. Skips of loggers
+// derived with AddCallerSkip add up, loggers derived with With and
+// WithGroup keep them.
+func AddCallerSkip(logger *slog.Logger, skip int) *slog.Logger {
+	if h, ok := logger.Handler().(callerSkipHandler); ok {
+		h.skip += skip
+
+		return slog.New(h)
+	}
+
+	return slog.New(callerSkipHandler{Handler: logger.Handler(), skip: skip})
+}
+
+// callerSkipHandler moves the PC of records up the stack.
+type callerSkipHandler struct {
+	slog.Handler
+
+	skip int
+}
+
+func (h callerSkipHandler) Handle(ctx context.Context, record slog.Record) error {
+	// The PC is left as it is if the record has been logged by another
+	// goroutine or there are not enough frames.
+	if pc, ok := stacktrace.Caller(record.PC, h.skip); ok {
+		record.PC = pc
+	}
+
+	return h.Handler.Handle(ctx, record)
+}
+
+func (h callerSkipHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	h.Handler = h.Handler.WithAttrs(attrs)
+
+	return h
+}
+
+func (h callerSkipHandler) WithGroup(name string) slog.Handler {
+	h.Handler = h.Handler.WithGroup(name)
+
+	return h
+}
@@ -0,0 +1,144 @@
+package tlog_test
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/tarantool/go-tlog"
+)
+
+const testFunction = "github.com/tarantool/go-tlog_test."
+
+// firstFrame returns the function of the first frame of a stacktrace.
+func firstFrame(stack string) string {
+	function, _, _ := strings.Cut(stack, "\n")
+	return function
+}
+
+type jsonRecord struct {
+	Msg    string      `json:"msg"`
+	Source slog.Source `json:"source"`
+	Stack  string      `json:"stacktrace"`
+}
+
+// readJSONRecords returns the records of a JSON log file.
+func readJSONRecords(t *testing.T, path string) []jsonRecord {
+	t.Helper()
+
+	lines := readLogLines(t, path)
+	records := make([]jsonRecord, len(lines))
+
+	for i, line := range lines {
+		require.NoError(t, json.Unmarshal([]byte(line), &records[i]))
+	}
+
+	return records
+}
+
+func Test_Logger_Stacktrace_Anchored(t *testing.T) {
+	t.Parallel()
+
+	r := require.New(t)
+
+	path := filepath.Join(t.TempDir(), "app.json")
+
+	l, err := tlog.New(tlog.Opts{Level: tlog.LevelTrace, Format: tlog.FormatJSON, Path: path})
+	r.NoError(err)
+
+	ctx := context.Background()
+	logger := l.Logger()
+
+	logger.Error("error")
+	logger.Log(ctx, slog.LevelError, "log")
+	logger.LogAttrs(ctx, slog.LevelError, "log attrs")
+	tlog.TraceContext(ctx, logger, "trace", tlog.Stacktrace(true))
+	// The standard log package has its own call depth.
+	slog.NewLogLogger(logger.Handler(), slog.LevelError).Print("log logger")
+
+	r.NoError(l.Close())
+
+	records := readJSONRecords(t, path)
+	r.Len(records, 5)
+
+	for _, record := range records {
+		r.Equal(testFunction+"Test_Logger_Stacktrace_Anchored", firstFrame(record.Stack), record.Msg)
+		r.Equal(testFunction+"Test_Logger_Stacktrace_Anchored", record.Source.Function, record.Msg)
+	}
+}
+
+// logRequest is a logging helper skipped with AddCallerSkip.
+func logRequest(logger *slog.Logger, id int) {
+	logger.Error("request failed", "id", id)
+}
+
+// logRequestTwice is a helper of a helper skipped with AddCallerSkip.
+func logRequestTwice(logger *slog.Logger, id int) {
+	logRequest(logger, id)
+}
+
+func Test_AddCallerSkip(t *testing.T) {
+	t.Parallel()
+
+	r := require.New(t)
+
+	path := filepath.Join(t.TempDir(), "app.json")
+
+	l, err := tlog.New(tlog.Opts{Format: tlog.FormatJSON, Path: path})
+	r.NoError(err)
+
+	logger := tlog.AddCallerSkip(l.Logger(), 1)
+
+	logRequest(logger, 1)
+	line := line() - 1
+
+	logRequest(logger.With("component", "x"), 2)
+	logRequestTwice(tlog.AddCallerSkip(logger, 1), 3)
+
+	// Skipping more frames than the stack has leaves the record as it is.
+	logRequest(tlog.AddCallerSkip(l.Logger(), 1000), 4)
+
+	r.NoError(l.Close())
+
+	records := readJSONRecords(t, path)
+	r.Len(records, 4)
+
+	for _, record := range records[:3] {
+		r.Equal(testFunction+"Test_AddCallerSkip", record.Source.Function)
+		r.Equal(testFunction+"Test_AddCallerSkip", firstFrame(record.Stack))
+		r.NotContains(record.Stack, "logRequest")
+	}
+
+	r.Equal(line, records[0].Source.Line)
+
+	r.Equal(testFunction+"logRequest", records[3].Source.Function)
+	r.Equal(testFunction+"logRequest", firstFrame(records[3].Stack))
+}
+
+func Test_AddCallerSkip_Text(t *testing.T) {
+	t.Parallel()
+
+	r := require.New(t)
+
+	path := filepath.Join(t.TempDir(), "app.log")
+
+	l, err := tlog.New(tlog.Opts{Path: path})
+	r.NoError(err)
+
+	logRequest(tlog.AddCallerSkip(l.Logger(), 1).WithGroup("req"), 1)
+	line := line() - 1
+
+	r.NoError(l.Close())
+
+	lines := readLogLines(t, path)
+	r.Len(lines, 1)
+	r.Contains(lines[0], "caller_test.go:"+strconv.Itoa(line)+` "request failed" stacktrace="`+
+		testFunction+"Test_AddCallerSkip_Text")
+	r.Contains(lines[0], " req.id=1")
+}
@@ -0,0 +1,227 @@
+package tlog
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+
+	"github.com/tarantool/go-tlog/internal/outputs"
+)
+
+// levelWriter writes records encoded at the given level.
+type levelWriter interface {
+	WriteLevel(level slog.Level, p []byte) (int, error)
+}
+
+// output is a log destination with its own minimum level.
+type output struct {
+	w     levelWriter
+	level slog.Leveler
+}
+
+// fanout is the state shared by a fanoutHandler and its derivatives.
+type fanout struct {
+	outputs []output
+
+	// mu serializes records, so that level is the level of the record
+	// being written.
+	mu    sync.Mutex
+	level slog.Level
+
+	// onError is called after an error record is written, if set.
+	onError func()
+}
+
+func (f *fanout) enabled(level slog.Level) bool {
+	return enabled(f.outputs, level)
+}
+
+// Level returns the lowest level accepted by any of the outputs.
+// It implements slog.Leveler, as the levels may change.
+func (f *fanout) Level() slog.Level {
+	level := f.outputs[0].level.Level()
+
+	for _, out := range f.outputs[1:] {
+		level = min(level, out.level.Level())
+	}
+
+	return level
+}
+
+func enabled(outputs []output, level slog.Level) bool {
+	for _, out := range outputs {
+		if level >= out.level.Level() {
+			return true
+		}
+	}
+
+	return false
+}
+
+// encoding is a set of outputs sharing a format. Records are encoded
+// once per encoding and the bytes are written to each of its outputs.
+type encoding struct {
+	fanout  *fanout
+	format  Format
+	outputs []output
+}
+
+// Write writes p to the outputs accepting the current record.
+func (e *encoding) Write(p []byte) (int, error) {
+	errs := make([]error, 0, len(e.outputs))
+
+	for _, out := range e.outputs {
+		if e.fanout.level >= out.level.Level() {
+			_, err := out.w.WriteLevel(e.fanout.level, p)
+			errs = append(errs, err)
+		}
+	}
+
+	return len(p), errors.Join(errs...)
+}
+
+// fanoutHandler encodes a record once per distinct format and writes it
+// to every output accepting the record level.
+type fanoutHandler struct {
+	fanout    *fanout
+	encodings []*encoding
+	// handlers are encoders of encodings with the same indexes.
+	handlers []slog.Handler
+}
+
+// newFanoutHandler creates a fanoutHandler over outs. Outputs without
+// level or format modifiers use the given level and format. Records are
+// encoded with handlerOpts of their format.
+func newFanoutHandler(outs *outputs.Outputs, level slog.Leveler, format Format,
+	handlerOpts func(Format) slog.HandlerOptions,
+) (fanoutHandler, error) {
+	list := outs.List()
+	h := fanoutHandler{fanout: &fanout{outputs: make([]output, 0, len(list))}}
+
+	for _, out := range list {
+		outLevel, outFormat, err := parseModifiers(out, level, format)
+		if err != nil {
+			return fanoutHandler{}, err
+		}
+
+		o := output{w: out, level: outLevel}
+		h.fanout.outputs = append(h.fanout.outputs, o)
+
+		i := h.encodingIndex(outFormat)
+		if i < 0 {
+			i = len(h.encodings)
+			h.encodings = append(h.encodings, &encoding{fanout: h.fanout, format: outFormat})
+		}
+
+		h.encodings[i].outputs = append(h.encodings[i].outputs, o)
+	}
+
+	for _, e := range h.encodings {
+		opts := handlerOpts(e.format)
+		opts.Level = h.fanout
+
+		h.handlers = append(h.handlers, newHandler(e.format, e, opts))
+	}
+
+	return h, nil
+}
+
+func (h fanoutHandler) encodingIndex(format Format) int {
+	for i, e := range h.encodings {
+		if e.format == format {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// parseModifiers returns the level and the format of out.
+func parseModifiers(out *outputs.Output, level slog.Leveler, format Format) (slog.Leveler, Format, error) {
+	var hasLevel, hasFormat bool
+
+	for _, modifier := range out.Modifiers {
+		if l, ok := parseLevel(modifier); ok {
+			if hasLevel {
+				return nil, 0, fmt.Errorf("path %q has more than one level", out.Path)
+			}
+
+			hasLevel, level = true, l.slogLevel()
+
+			continue
+		}
+
+		if f, ok := parseFormat(modifier); ok {
+			if hasFormat {
+				return nil, 0, fmt.Errorf("path %q has more than one format", out.Path)
+			}
+
+			hasFormat, format = true, f
+		}
+	}
+
+	return level, format, nil
+}
+
+// isModifier reports whether name is a modifier of an Opts.Path entry.
+func isModifier(name string) bool {
+	_, isLevel := parseLevel(name)
+	_, isFormat := parseFormat(name)
+
+	return isLevel || isFormat
+}
+
+// Enabled reports whether any of the outputs accepts the level.
+func (h fanoutHandler) Enabled(_ context.Context, level slog.Level) bool {
+	return h.fanout.enabled(level)
+}
+
+func (h fanoutHandler) Handle(ctx context.Context, record slog.Record) error {
+	err := h.handle(ctx, record)
+
+	if record.Level >= slog.LevelError && h.fanout.onError != nil {
+		h.fanout.onError()
+	}
+
+	return err
+}
+
+func (h fanoutHandler) handle(ctx context.Context, record slog.Record) error {
+	h.fanout.mu.Lock()
+	defer h.fanout.mu.Unlock()
+
+	h.fanout.level = record.Level
+
+	errs := make([]error, 0, len(h.handlers))
+
+	for i, handler := range h.handlers {
+		if enabled(h.encodings[i].outputs, record.Level) {
+			errs = append(errs, handler.Handle(ctx, record))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func (h fanoutHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return h.derive(func(handler slog.Handler) slog.Handler {
+		return handler.WithAttrs(attrs)
+	})
+}
+
+func (h fanoutHandler) WithGroup(name string) slog.Handler {
+	return h.derive(func(handler slog.Handler) slog.Handler {
+		return handler.WithGroup(name)
+	})
+}
+
+func (h fanoutHandler) derive(with func(slog.Handler) slog.Handler) fanoutHandler {
+	handlers := make([]slog.Handler, len(h.handlers))
+	for i, handler := range h.handlers {
+		handlers[i] = with(handler)
+	}
+
+	return fanoutHandler{fanout: h.fanout, encodings: h.encodings, handlers: handlers}
+}
@@ -0,0 +1,239 @@
+package tlog_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/tarantool/go-tlog"
+)
+
+func Test_Logger_PerOutputLevel(t *testing.T) {
+	t.Parallel()
+
+	r := require.New(t)
+
+	dir := t.TempDir()
+	errorsPath := filepath.Join(dir, "errors.log")
+	debugPath := filepath.Join(dir, "debug.log")
+	defaultPath := filepath.Join(dir, "default.log")
+
+	l, err := tlog.New(tlog.Opts{
+		Level: tlog.LevelWarn,
+		Path:  errorsPath + ":error," + debugPath + ":DEBUG," + defaultPath,
+	})
+	r.NoError(err)
+
+	l.Logger().Debug("debug message")
+	l.Logger().Info("info message")
+	l.Logger().Warn("warn message")
+	l.Logger().Error("error message")
+
+	r.NoError(l.Close())
+
+	errorsLogs, err := os.ReadFile(errorsPath)
+	r.NoError(err)
+	r.NotContains(string(errorsLogs), "warn message")
+	r.Contains(string(errorsLogs), "error message")
+
+	debugLogs, err := os.ReadFile(debugPath)
+	r.NoError(err)
+	r.Contains(string(debugLogs), "debug message")
+	r.Contains(string(debugLogs), "info message")
+	r.Contains(string(debugLogs), "error message")
+
+	defaultLogs, err := os.ReadFile(defaultPath)
+	r.NoError(err)
+	r.NotContains(string(defaultLogs), "info message")
+	r.Contains(string(defaultLogs), "warn message")
+	r.Contains(string(defaultLogs), "error message")
+}
+
+func Test_Logger_PerOutputLevel_Enabled(t *testing.T) {
+	t.Parallel()
+
+	r := require.New(t)
+
+	dir := t.TempDir()
+
+	l, err := tlog.New(tlog.Opts{
+		Level: tlog.LevelError,
+		Path:  filepath.Join(dir, "a.log") + "," + filepath.Join(dir, "b.log") + ":info",
+	})
+	r.NoError(err)
+
+	defer func() {
+		_ = l.Close()
+	}()
+
+	ctx := context.Background()
+
+	r.False(l.Logger().Enabled(ctx, slog.LevelDebug))
+	r.True(l.Logger().Enabled(ctx, slog.LevelInfo))
+	r.True(l.Logger().Enabled(ctx, slog.LevelError))
+
+	r.True(l.Logger().With("key", "value").Enabled(ctx, slog.LevelInfo))
+}
+
+func Test_Logger_PerOutputLevel_Several(t *testing.T) {
+	t.Parallel()
+
+	_, err := tlog.New(tlog.Opts{
+		Path: filepath.Join(t.TempDir(), "a.log") + ":info:error",
+	})
+	require.ErrorContains(t, err, "has more than one level")
+}
+
+// countingValuer counts how many times it is resolved.
+type countingValuer struct {
+	resolved atomic.Int32
+}
+
+func (v *countingValuer) LogValue() slog.Value {
+	v.resolved.Add(1)
+	return slog.StringValue("counted")
+}
+
+func Test_Logger_PerOutputFormat(t *testing.T) {
+	t.Parallel()
+
+	r := require.New(t)
+
+	dir := t.TempDir()
+	textPath := filepath.Join(dir, "app.log")
+	jsonPath1 := filepath.Join(dir, "app1.json")
+	jsonPath2 := filepath.Join(dir, "app2.json")
+
+	l, err := tlog.New(tlog.Opts{
+		Format: tlog.FormatText,
+		Path:   textPath + "," + jsonPath1 + ":json," + jsonPath2 + ":debug:json",
+	})
+	r.NoError(err)
+
+	valuer := &countingValuer{}
+
+	l.Logger().
+		With("component", "fanout").
+		WithGroup("request").
+		Error("request failed", "id", 42, "valuer", valuer)
+
+	r.NoError(l.Close())
+
+	// Encoded once for the text and once for both JSON outputs.
+	r.Equal(int32(2), valuer.resolved.Load())
+
+	jsonLogs1, err := os.ReadFile(jsonPath1)
+	r.NoError(err)
+
+	jsonLogs2, err := os.ReadFile(jsonPath2)
+	r.NoError(err)
+	r.Equal(string(jsonLogs1), string(jsonLogs2))
+
+	var record map[string]any
+	r.NoError(json.Unmarshal(jsonLogs1, &record))
+
+	r.Equal("request failed", record["msg"])
+	r.Equal("ERROR", record["level"])
+	r.Equal("fanout", record["component"])
+	r.Equal(map[string]any{"id": float64(42), "valuer": "counted"}, record["request"])
+	r.Contains(record["stacktrace"], "Test_Logger_PerOutputFormat")
+
+	textLogs, err := os.ReadFile(textPath)
+	r.NoError(err)
+
+	text := string(textLogs)
+	r.Contains(text, `ERROR`)
+	r.Contains(text, `"request failed"`)
+	r.Contains(text, `component=fanout`)
+	r.Contains(text, `request.id=42`)
+	r.Contains(text, `request.valuer=counted`)
+}
+
+func Test_Logger_PerOutputFormat_Several(t *testing.T) {
+	t.Parallel()
+
+	_, err := tlog.New(tlog.Opts{
+		Path: filepath.Join(t.TempDir(), "a.log") + ":json:text",
+	})
+	require.ErrorContains(t, err, "has more than one format")
+}
+
+// failingSink fails every write.
+type failingSink struct{}
+
+func (failingSink) Write([]byte) (int, error) {
+	return 0, errors.New("disk full")
+}
+
+func (failingSink) Close() error {
+	return nil
+}
+
+func Test_Logger_FailingOutput(t *testing.T) {
+	t.Parallel()
+
+	r := require.New(t)
+
+	scheme := registerSink(t, func(*url.URL) (io.WriteCloser, error) {
+		return failingSink{}, nil
+	})
+	failing := scheme + "://disk"
+
+	dir := t.TempDir()
+	textPath := filepath.Join(dir, "text.log")
+	jsonPath := filepath.Join(dir, "json.log")
+
+	var (
+		mu      sync.Mutex
+		handled []string
+	)
+
+	l, err := tlog.New(tlog.Opts{
+		Level: tlog.LevelInfo,
+		Path:  textPath + "," + failing + ":json," + jsonPath + ":json",
+		ErrorHandler: func(output string, err error) {
+			mu.Lock()
+			defer mu.Unlock()
+
+			handled = append(handled, output+": "+err.Error())
+		},
+	})
+	r.NoError(err)
+
+	l.Logger().Info("first message")
+	l.Logger().Info("second message")
+
+	r.NoError(l.Close())
+
+	textLogs, err := os.ReadFile(textPath)
+	r.NoError(err)
+	r.Contains(string(textLogs), "first message")
+	r.Contains(string(textLogs), "second message")
+
+	jsonLogs, err := os.ReadFile(jsonPath)
+	r.NoError(err)
+	r.Contains(string(jsonLogs), "first message")
+	r.Contains(string(jsonLogs), "second message")
+
+	r.Equal(map[string]uint64{
+		textPath: 0,
+		failing:  2,
+		jsonPath: 0,
+	}, l.OutputErrors())
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	// The second failure is within a second of the first one.
+	r.Equal([]string{failing + ": disk full"}, handled)
+}
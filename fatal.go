@@ -0,0 +1,49 @@
+package tlog
+
+import "context"
+
+// fatalExitCode is the exit code of Fatal.
+const fatalExitCode = 1
+
+// Fatal logs at LevelFatalSlog, closes the logger, so that the record
+// and all the previous ones reach the outputs, and calls Opts.Exit
+// with a non-zero code.
+func (l *Logger) Fatal(msg string, args ...any) {
+	logAt(context.Background(), l.logger, LevelFatalSlog, msg, args...)
+	l.fatal()
+}
+
+// FatalContext is Fatal with the given context.
+func (l *Logger) FatalContext(ctx context.Context, msg string, args ...any) {
+	logAt(ctx, l.logger, LevelFatalSlog, msg, args...)
+	l.fatal()
+}
+
+func (l *Logger) fatal() {
+	// The process is exiting, there is nowhere to report the error.
+	_ = l.Close()
+
+	l.exit(fatalExitCode)
+}
+
+// Panic logs at LevelPanicSlog with a stacktrace, syncs the logger, so
+// that the record reaches the outputs even if the panic crashes the
+// process, and panics with msg. Unlike Fatal, it does not close
+// the logger, so the panic may be recovered.
+func (l *Logger) Panic(msg string, args ...any) {
+	logAt(context.Background(), l.logger, LevelPanicSlog, msg, args...)
+	l.panic(msg)
+}
+
+// PanicContext is Panic with the given context.
+func (l *Logger) PanicContext(ctx context.Context, msg string, args ...any) {
+	logAt(ctx, l.logger, LevelPanicSlog, msg, args...)
+	l.panic(msg)
+}
+
+func (l *Logger) panic(msg string) {
+	// The process may be crashing, there is nowhere to report the error.
+	_ = l.Sync()
+
+	panic(msg)
+}
@@ -0,0 +1,154 @@
+package tlog_test
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/tarantool/go-tlog"
+)
+
+func Test_Logger_Fatal(t *testing.T) {
+	t.Parallel()
+
+	r := require.New(t)
+
+	path := filepath.Join(t.TempDir(), "app.log")
+	codes := []int{}
+
+	l, err := tlog.New(tlog.Opts{
+		Path:  path,
+		Async: tlog.Async{QueueSize: 1024},
+		Exit:  func(code int) { codes = append(codes, code) },
+	})
+	r.NoError(err)
+
+	for range 100 {
+		l.Logger().Info("queued message")
+	}
+
+	l.Fatal("fatal message", "key", "value")
+
+	r.Equal([]int{1}, codes)
+
+	logs, err := os.ReadFile(path)
+	r.NoError(err)
+
+	lines := strings.Split(strings.TrimSuffix(string(logs), "\n"), "\n")
+	r.Len(lines, 101)
+	r.Contains(lines[100], ` FATAL `)
+	r.Contains(lines[100], `fatal_test.go:`)
+	r.Contains(lines[100], `"fatal message" key=value stacktrace=`)
+
+	// The logger is closed.
+	r.NoError(l.Close())
+}
+
+func Test_Logger_Fatal_JSON(t *testing.T) {
+	t.Parallel()
+
+	r := require.New(t)
+
+	path := filepath.Join(t.TempDir(), "app.log")
+	exited := false
+
+	l, err := tlog.New(tlog.Opts{
+		Level:  tlog.LevelError,
+		Format: tlog.FormatJSON,
+		Path:   path,
+		Exit:   func(int) { exited = true },
+	})
+	r.NoError(err)
+
+	l.Fatal("fatal message")
+	r.True(exited)
+
+	logs, err := os.ReadFile(path)
+	r.NoError(err)
+
+	var record map[string]any
+
+	r.NoError(json.Unmarshal(logs, &record))
+	r.Equal("FATAL", record["level"])
+	r.Equal("fatal message", record["msg"])
+	r.Contains(record["stacktrace"], "Test_Logger_Fatal_JSON")
+}
+
+func Test_Logger_Panic(t *testing.T) {
+	t.Parallel()
+
+	r := require.New(t)
+
+	dir := t.TempDir()
+	textPath := filepath.Join(dir, "app.log")
+	jsonPath := filepath.Join(dir, "app.json")
+
+	l, err := tlog.New(tlog.Opts{Path: textPath + "," + jsonPath + ":json"})
+	r.NoError(err)
+
+	r.PanicsWithValue("panic message", func() {
+		l.Panic("panic message", "key", "value")
+	})
+
+	// Unlike Fatal, Panic leaves the logger open.
+	l.Logger().Info("recovered")
+	r.NoError(l.Close())
+
+	logs, err := os.ReadFile(textPath)
+	r.NoError(err)
+
+	lines := strings.Split(strings.TrimSuffix(string(logs), "\n"), "\n")
+	r.Len(lines, 2)
+	r.Contains(lines[0], ` PANIC `)
+	r.Contains(lines[0], `"panic message" key=value stacktrace=`)
+	r.Contains(lines[0], "Test_Logger_Panic")
+	r.Contains(lines[1], "recovered")
+
+	logs, err = os.ReadFile(jsonPath)
+	r.NoError(err)
+
+	var record map[string]any
+
+	r.NoError(json.NewDecoder(strings.NewReader(string(logs))).Decode(&record))
+	r.Equal("PANIC", record["level"])
+	r.Equal("value", record["key"])
+}
+
+func Test_Logger_Panic_Async(t *testing.T) {
+	t.Parallel()
+
+	r := require.New(t)
+
+	path := filepath.Join(t.TempDir(), "app.log")
+
+	l, err := tlog.New(tlog.Opts{
+		Path:  path,
+		Async: tlog.Async{QueueSize: 1024},
+	})
+	r.NoError(err)
+
+	defer func() {
+		r.NoError(l.Close())
+	}()
+
+	for range 100 {
+		l.Logger().Info("queued message")
+	}
+
+	r.Panics(func() {
+		l.Panic("panic message")
+	})
+
+	// The queue is flushed before panicking.
+	logs, err := os.ReadFile(path)
+	r.NoError(err)
+
+	lines := strings.Split(strings.TrimSuffix(string(logs), "\n"), "\n")
+	r.Len(lines, 101)
+	r.Contains(lines[100], ` PANIC `)
+	r.Contains(lines[100], `"panic message" stacktrace=`)
+}
@@ -1,5 +1,10 @@
 package tlog
 
+import (
+	"fmt"
+	"strings"
+)
+
 // Format represents logger format.
 type Format int
 
@@ -11,3 +16,78 @@ const (
 	// FormatJSON prints each message as a JSON object.
 	FormatJSON
 )
+
+// formatNames are names of formats accepted as per-output modifiers,
+// e.g. "stdout:json".
+var formatNames = map[string]Format{
+	"text":  FormatText,
+	"plain": FormatText,
+	"json":  FormatJSON,
+}
+
+// String returns the name of f as accepted in Opts.Path, e.g. "json".
+func (f Format) String() string {
+	switch f {
+	case FormatDefault:
+		return "default"
+	case FormatText:
+		return "text"
+	case FormatJSON:
+		return "json"
+	default:
+		return fmt.Sprintf("Format(%d)", int(f))
+	}
+}
+
+// ParseFormat returns the format with the given name, "text" (or "plain")
+// or "json". Names are case-insensitive.
+func ParseFormat(s string) (Format, error) {
+	if format, ok := parseFormat(s); ok {
+		return format, nil
+	}
+
+	if strings.EqualFold(s, FormatDefault.String()) {
+		return FormatDefault, nil
+	}
+
+	return 0, fmt.Errorf("unknown format %q", s)
+}
+
+// valid reports whether f is one of the defined formats.
+func (f Format) valid() bool {
+	return f >= FormatDefault && f <= FormatJSON
+}
+
+// MarshalText implements encoding.TextMarshaler. It returns the name
+// of f, see String.
+func (f Format) MarshalText() ([]byte, error) {
+	if !f.valid() {
+		return nil, fmt.Errorf("unknown format %d", int(f))
+	}
+
+	return []byte(f.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler, so formats can be
+// read from JSON, YAML and other configuration files. It accepts the
+// same names as ParseFormat.
+func (f *Format) UnmarshalText(text []byte) error {
+	format, err := ParseFormat(string(text))
+	if err != nil {
+		return err
+	}
+
+	*f = format
+
+	return nil
+}
+
+// Set implements flag.Value, so a format can be a command-line flag.
+func (f *Format) Set(s string) error {
+	return f.UnmarshalText([]byte(s))
+}
+
+func parseFormat(name string) (Format, bool) {
+	format, ok := formatNames[strings.ToLower(name)]
+	return format, ok
+}
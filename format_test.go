@@ -0,0 +1,36 @@
+package tlog_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/tarantool/go-tlog"
+)
+
+func Test_Format_Text(t *testing.T) {
+	t.Parallel()
+
+	r := require.New(t)
+
+	for _, format := range []tlog.Format{tlog.FormatDefault, tlog.FormatText, tlog.FormatJSON} {
+		text, err := format.MarshalText()
+		r.NoError(err)
+
+		var parsed tlog.Format
+
+		r.NoError(parsed.UnmarshalText(text))
+		r.Equal(format, parsed, string(text))
+	}
+
+	_, err := tlog.Format(42).MarshalText()
+	r.EqualError(err, "unknown format 42")
+	r.Equal("Format(42)", tlog.Format(42).String())
+
+	format, err := tlog.ParseFormat("Plain")
+	r.NoError(err)
+	r.Equal(tlog.FormatText, format)
+
+	_, err = tlog.ParseFormat("yaml")
+	r.EqualError(err, `unknown format "yaml"`)
+}
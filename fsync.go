@@ -0,0 +1,51 @@
+package tlog
+
+import (
+	"time"
+
+	"github.com/tarantool/go-tlog/internal/outputs"
+)
+
+// FsyncPolicy defines when file outputs are committed to stable storage.
+type FsyncPolicy int
+
+const (
+	// FsyncNever leaves syncing to the operating system and Logger.Sync.
+	FsyncNever FsyncPolicy = iota
+	// FsyncLevel syncs a file after each record at or above Fsync.Level.
+	FsyncLevel
+	// FsyncInterval syncs files every Fsync.Interval.
+	FsyncInterval
+)
+
+// Fsync configures syncing of file outputs and custom sinks having
+// a Sync() error method. The zero value never syncs them, except in
+// Logger.Sync and Logger.Close.
+type Fsync struct {
+	// Policy defines when outputs are synced.
+	Policy FsyncPolicy
+	// Level is the minimum level of records followed by a sync with
+	// FsyncLevel, e.g. LevelError. Default is LevelInfo.
+	Level Level
+	// Interval is the period of syncing with FsyncInterval.
+	// It must be positive with that policy.
+	Interval time.Duration
+}
+
+func (f Fsync) policy() outputs.SyncPolicy {
+	switch f.Policy {
+	case FsyncLevel:
+		return outputs.SyncLevel
+	case FsyncInterval:
+		return outputs.SyncInterval
+	default:
+		return outputs.SyncNever
+	}
+}
+
+// Sync writes out records queued by asynchronous logging and commits
+// file outputs to stable storage, e.g. before acknowledging a critical
+// operation.
+func (l *Logger) Sync() error {
+	return l.outputs.Sync()
+}
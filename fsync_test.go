@@ -0,0 +1,95 @@
+package tlog_test
+
+import (
+	"io"
+	"net/url"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/tarantool/go-tlog"
+)
+
+// syncCounter is a fake file counting writes and syncs.
+type syncCounter struct {
+	mu     sync.Mutex
+	writes int
+	syncs  int
+}
+
+func (c *syncCounter) Write(p []byte) (int, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.writes++
+
+	return len(p), nil
+}
+
+func (c *syncCounter) Sync() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.syncs++
+
+	return nil
+}
+
+func (c *syncCounter) Close() error {
+	return nil
+}
+
+func (c *syncCounter) Counts() (writes, syncs int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.writes, c.syncs
+}
+
+func Test_Logger_Fsync(t *testing.T) {
+	t.Parallel()
+
+	r := require.New(t)
+
+	file := &syncCounter{}
+
+	scheme := registerSink(t, func(*url.URL) (io.WriteCloser, error) {
+		return file, nil
+	})
+
+	l, err := tlog.New(tlog.Opts{
+		Level: tlog.LevelDebug,
+		Path:  scheme + "://file",
+		Fsync: tlog.Fsync{
+			Policy: tlog.FsyncLevel,
+			Level:  tlog.LevelWarn,
+		},
+	})
+	r.NoError(err)
+
+	l.Logger().Debug("debug message")
+	l.Logger().Info("info message")
+
+	writes, syncs := file.Counts()
+	r.Equal(2, writes)
+	r.Equal(0, syncs)
+
+	l.Logger().Warn("warn message")
+	l.Logger().Error("error message")
+
+	writes, syncs = file.Counts()
+	r.Equal(4, writes)
+	r.Equal(2, syncs)
+
+	r.NoError(l.Sync())
+
+	_, syncs = file.Counts()
+	r.Equal(3, syncs)
+
+	// Close syncs the outputs first.
+	r.NoError(l.Close())
+
+	_, syncs = file.Counts()
+	r.Equal(4, syncs)
+}
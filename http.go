@@ -0,0 +1,138 @@
+package tlog
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"mime"
+	"net/http"
+	"time"
+)
+
+// levelState is the body of LevelHandler responses.
+type levelState struct {
+	Level string `json:"level"`
+	// RestoreLevel is the level restored at RestoreAt after a temporary
+	// change, if any.
+	RestoreLevel string    `json:"restore_level,omitempty"`
+	RestoreAt    time.Time `json:"restore_at,omitzero"`
+}
+
+// levelRequest is the body of a LevelHandler PUT request.
+type levelRequest struct {
+	Level    string `json:"level"`
+	Duration string `json:"duration"`
+}
+
+type levelError struct {
+	Error string `json:"error"`
+}
+
+// maxLevelRequestSize limits the body of a LevelHandler PUT request.
+const maxLevelRequestSize = 4 << 10
+
+// LevelHandler returns an HTTP handler to inspect and change the level
+// of the logger at runtime, see SetLevel.
+//
+// GET responds with the current level as JSON: {"level":"info"}.
+//
+// PUT sets the level given by name or Tarantool number, see ParseLevel,
+// either as JSON, {"level":"debug"}, or as a form, level=debug. An
+// optional duration, e.g. "10m", makes the change temporary: the previous
+// level is restored after it.
+// The response is the same as for GET, with the level to be restored
+// and the time of the restore for a temporary change:
+//
+//	curl -X PUT -d level=debug -d duration=10m http://localhost:8080/log/level
+func (l *Logger) LevelHandler() http.Handler {
+	return http.HandlerFunc(l.serveLevel)
+}
+
+func (l *Logger) serveLevel(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+	case http.MethodPut:
+		r.Body = http.MaxBytesReader(w, r.Body, maxLevelRequestSize)
+
+		level, d, err := parseLevelRequest(r)
+		if err != nil {
+			writeJSON(w, http.StatusBadRequest, levelError{Error: err.Error()})
+
+			return
+		}
+
+		l.setLevelFor(level, d)
+	default:
+		w.Header().Set("Allow", "GET, PUT")
+		writeJSON(w, http.StatusMethodNotAllowed, levelError{
+			Error: fmt.Sprintf("method %s is not allowed", r.Method),
+		})
+
+		return
+	}
+
+	writeJSON(w, http.StatusOK, l.levelState())
+}
+
+func (l *Logger) levelState() levelState {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	state := levelState{Level: l.level.String()}
+	if l.restore != nil {
+		state.RestoreLevel = l.restoreLevel.String()
+		state.RestoreAt = l.restoreAt
+	}
+
+	return state
+}
+
+// parseLevelRequest returns the level and the duration of a PUT request.
+func parseLevelRequest(r *http.Request) (Level, time.Duration, error) {
+	var req levelRequest
+
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+
+	if mediaType == "application/x-www-form-urlencoded" {
+		if err := r.ParseForm(); err != nil {
+			return 0, 0, fmt.Errorf("failed to parse form: %w", err)
+		}
+
+		req.Level = r.PostForm.Get("level")
+		req.Duration = r.PostForm.Get("duration")
+	} else if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return 0, 0, fmt.Errorf("failed to parse JSON: %w", err)
+	}
+
+	if req.Level == "" {
+		return 0, 0, errors.New("level is required")
+	}
+
+	level, err := ParseLevel(req.Level)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	if req.Duration == "" {
+		return level, 0, nil
+	}
+
+	d, err := time.ParseDuration(req.Duration)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid duration: %w", err)
+	}
+
+	if d <= 0 {
+		return 0, 0, fmt.Errorf("duration must be positive, got %s", d)
+	}
+
+	return level, d, nil
+}
+
+func writeJSON(w http.ResponseWriter, code int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+
+	// The client is gone if the response cannot be written.
+	_ = json.NewEncoder(w).Encode(v)
+}
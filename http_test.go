@@ -0,0 +1,206 @@
+package tlog_test
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/tarantool/go-tlog"
+)
+
+type levelResponse struct {
+	Level        string    `json:"level"`
+	RestoreLevel string    `json:"restore_level"`
+	RestoreAt    time.Time `json:"restore_at"`
+	Error        string    `json:"error"`
+}
+
+func serveLevel(t *testing.T, h http.Handler, req *http.Request) (int, levelResponse) {
+	t.Helper()
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+
+	require.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+
+	var resp levelResponse
+
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&resp))
+
+	return rec.Code, resp
+}
+
+func putLevel(body string) *http.Request {
+	return httptest.NewRequest(http.MethodPut, "/log/level", strings.NewReader(body))
+}
+
+func Test_Logger_LevelHandler(t *testing.T) {
+	t.Parallel()
+
+	r := require.New(t)
+
+	l, err := tlog.New(tlog.Opts{Path: "ring:10"})
+	r.NoError(err)
+
+	defer func() {
+		r.NoError(l.Close())
+	}()
+
+	h := l.LevelHandler()
+
+	code, resp := serveLevel(t, h, httptest.NewRequest(http.MethodGet, "/log/level", nil))
+	r.Equal(http.StatusOK, code)
+	r.Equal("info", resp.Level)
+	r.Empty(resp.RestoreLevel)
+	r.True(resp.RestoreAt.IsZero())
+
+	code, resp = serveLevel(t, h, putLevel(`{"level":"DEBUG"}`))
+	r.Equal(http.StatusOK, code)
+	r.Equal("debug", resp.Level)
+	r.Equal(tlog.LevelDebug, l.Level())
+
+	// Tarantool log levels are accepted too.
+	code, resp = serveLevel(t, h, putLevel(`{"level":"6"}`))
+	r.Equal(http.StatusOK, code)
+	r.Equal("verbose", resp.Level)
+
+	form := putLevel(url.Values{"level": {"error"}}.Encode())
+	form.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	code, resp = serveLevel(t, h, form)
+	r.Equal(http.StatusOK, code)
+	r.Equal("error", resp.Level)
+	r.Equal(tlog.LevelError, l.Level())
+}
+
+func Test_Logger_LevelHandler_Restore(t *testing.T) {
+	t.Parallel()
+
+	r := require.New(t)
+
+	l, err := tlog.New(tlog.Opts{Level: tlog.LevelWarn, Path: "ring:10"})
+	r.NoError(err)
+
+	defer func() {
+		r.NoError(l.Close())
+	}()
+
+	h := l.LevelHandler()
+	start := time.Now()
+
+	code, resp := serveLevel(t, h, putLevel(`{"level":"trace","duration":"1h"}`))
+	r.Equal(http.StatusOK, code)
+	r.Equal("trace", resp.Level)
+	r.Equal("warn", resp.RestoreLevel)
+	r.WithinDuration(start.Add(time.Hour), resp.RestoreAt, time.Minute)
+
+	// A temporary change replaces the previous one, but the level to be
+	// restored is kept.
+	code, resp = serveLevel(t, h, putLevel(`{"level":"debug","duration":"50ms"}`))
+	r.Equal(http.StatusOK, code)
+	r.Equal("debug", resp.Level)
+	r.Equal("warn", resp.RestoreLevel)
+
+	r.Eventually(func() bool {
+		return l.Level() == tlog.LevelWarn
+	}, 5*time.Second, 10*time.Millisecond)
+
+	_, resp = serveLevel(t, h, httptest.NewRequest(http.MethodGet, "/log/level", nil))
+	r.Equal("warn", resp.Level)
+	r.Empty(resp.RestoreLevel)
+
+	// SetLevel cancels the restore.
+	_, resp = serveLevel(t, h, putLevel(`{"level":"debug","duration":"50ms"}`))
+	r.Equal("warn", resp.RestoreLevel)
+
+	l.SetLevel(tlog.LevelError)
+
+	time.Sleep(100 * time.Millisecond)
+	r.Equal(tlog.LevelError, l.Level())
+}
+
+func Test_Logger_LevelHandler_Errors(t *testing.T) {
+	t.Parallel()
+
+	l, err := tlog.New(tlog.Opts{Path: "ring:10"})
+	require.NoError(t, err)
+
+	defer func() {
+		require.NoError(t, l.Close())
+	}()
+
+	h := l.LevelHandler()
+
+	tests := []struct {
+		name string
+		body string
+		err  string
+	}{
+		{"bad json", `{"level":`, "failed to parse JSON"},
+		{"no level", `{}`, "level is required"},
+		{"unknown level", `{"level":"verbose!"}`, `unknown level "verbose!"`},
+		{"bad duration", `{"level":"debug","duration":"soon"}`, "invalid duration"},
+		{"negative duration", `{"level":"debug","duration":"-1m"}`, "duration must be positive, got -1m0s"},
+		{"too large", `{"level":"debug","pad":"` + strings.Repeat("x", 8<<10) + `"}`, "request body too large"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := require.New(t)
+
+			code, resp := serveLevel(t, h, putLevel(tt.body))
+			r.Equal(http.StatusBadRequest, code)
+			r.Contains(resp.Error, tt.err)
+		})
+	}
+
+	r := require.New(t)
+	r.Equal(tlog.LevelInfo, l.Level())
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/log/level", nil))
+	r.Equal(http.StatusMethodNotAllowed, rec.Code)
+	r.Equal("GET, PUT", rec.Header().Get("Allow"))
+}
+
+func Test_Logger_LevelHandler_Server(t *testing.T) {
+	t.Parallel()
+
+	r := require.New(t)
+
+	l, err := tlog.New(tlog.Opts{Path: "ring:10"})
+	r.NoError(err)
+
+	defer func() {
+		r.NoError(l.Close())
+	}()
+
+	srv := httptest.NewServer(l.LevelHandler())
+	defer srv.Close()
+
+	resp, err := http.PostForm(srv.URL, url.Values{"level": {"debug"}})
+	r.NoError(err)
+	r.NoError(resp.Body.Close())
+	r.Equal(http.StatusMethodNotAllowed, resp.StatusCode)
+
+	req, err := http.NewRequest(http.MethodPut, srv.URL, strings.NewReader(`{"level":"debug"}`))
+	r.NoError(err)
+
+	resp, err = http.DefaultClient.Do(req)
+	r.NoError(err)
+
+	body, err := io.ReadAll(resp.Body)
+	r.NoError(err)
+	r.NoError(resp.Body.Close())
+
+	r.Equal(http.StatusOK, resp.StatusCode)
+	r.JSONEq(`{"level":"debug"}`, string(body))
+	r.Equal(tlog.LevelDebug, l.Level())
+}
@@ -0,0 +1,181 @@
+package outputs
+
+import (
+	"bytes"
+	"io"
+	"log/slog"
+	"os"
+	"sync"
+	"sync/atomic"
+)
+
+// Overflow is a policy of an asynchronous output with a full queue.
+type Overflow int
+
+const (
+	// OverflowBlock makes Write wait for a free slot in the queue.
+	OverflowBlock Overflow = iota
+	// OverflowDropNewest discards the record being written.
+	OverflowDropNewest
+	// OverflowDropOldest discards the oldest queued record to make room
+	// for the record being written.
+	OverflowDropOldest
+)
+
+// record is an encoded record waiting in the queue.
+type record struct {
+	level slog.Level
+	p     []byte
+	// flushed is closed once the records queued before it are written,
+	// it is set for flush markers only.
+	flushed chan struct{}
+}
+
+// async is an output written by a background goroutine. Records are
+// queued into a bounded queue, so Write does not wait for the underlying
+// writer unless the queue is full and the policy is OverflowBlock.
+type async struct {
+	w        io.WriteCloser
+	overflow Overflow
+	queue    chan record
+	dropped  atomic.Uint64
+	// onError is called on write failures of the background goroutine.
+	onError func(err error)
+
+	// mu guards closed: Write holds it for reading, so Close never
+	// closes the queue under a pending send.
+	mu     sync.RWMutex
+	closed bool
+	done   chan struct{}
+}
+
+func newAsync(w io.WriteCloser, size int, overflow Overflow, onError func(err error)) *async {
+	a := &async{
+		w:        w,
+		overflow: overflow,
+		onError:  onError,
+		queue:    make(chan record, size),
+		done:     make(chan struct{}),
+	}
+
+	go a.run()
+
+	return a
+}
+
+func (a *async) run() {
+	defer close(a.done)
+
+	for r := range a.queue {
+		if r.flushed != nil {
+			close(r.flushed)
+
+			continue
+		}
+
+		// There is no caller to return the error to.
+		if _, err := writeLevel(a.w, r.level, r.p); err != nil && a.onError != nil {
+			a.onError(err)
+		}
+	}
+}
+
+// Write queues a copy of p as a record of the informational level.
+func (a *async) Write(p []byte) (int, error) {
+	return a.WriteLevel(slog.LevelInfo, p)
+}
+
+// WriteLevel queues a copy of p. It always reports len(p) bytes written,
+// even if the record is dropped due to the overflow policy.
+func (a *async) WriteLevel(level slog.Level, p []byte) (int, error) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	if a.closed {
+		return 0, os.ErrClosed
+	}
+
+	record := record{level: level, p: bytes.Clone(p)}
+
+	switch a.overflow {
+	case OverflowDropNewest:
+		select {
+		case a.queue <- record:
+		default:
+			a.dropped.Add(1)
+		}
+	case OverflowDropOldest:
+		for {
+			select {
+			case a.queue <- record:
+				return len(p), nil
+			default:
+			}
+
+			select {
+			case r := <-a.queue:
+				if r.flushed != nil {
+					// The records before the marker are gone anyway.
+					close(r.flushed)
+				} else {
+					a.dropped.Add(1)
+				}
+			default:
+			}
+		}
+	default:
+		a.queue <- record
+	}
+
+	return len(p), nil
+}
+
+// Dropped returns the number of records dropped so far.
+func (a *async) Dropped() uint64 {
+	return a.dropped.Load()
+}
+
+// Flush waits until the records queued so far are written, regardless
+// of the overflow policy.
+func (a *async) Flush() error {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	if a.closed {
+		return os.ErrClosed
+	}
+
+	flushed := make(chan struct{})
+	a.queue <- record{flushed: flushed}
+	<-flushed
+
+	return nil
+}
+
+// Reopen reopens the underlying output, if it supports reopening.
+func (a *async) Reopen() error {
+	if r, ok := a.w.(reopener); ok {
+		return r.Reopen()
+	}
+
+	return nil
+}
+
+// Close writes out all queued records and closes the underlying output.
+func (a *async) Close() error {
+	a.mu.Lock()
+
+	if a.closed {
+		a.mu.Unlock()
+
+		return nil
+	}
+
+	a.closed = true
+	close(a.queue)
+	a.mu.Unlock()
+
+	<-a.done
+
+	return closeOutput(a.w)
+}
@@ -0,0 +1,165 @@
+package outputs_test
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/tarantool/go-tlog/internal/outputs"
+)
+
+// gatedWriter blocks every Write until it is released.
+type gatedWriter struct {
+	mu      sync.Mutex
+	records []string
+	started chan struct{}
+	release chan struct{}
+}
+
+func newGatedWriter() *gatedWriter {
+	return &gatedWriter{
+		started: make(chan struct{}, 100),
+		release: make(chan struct{}),
+	}
+}
+
+func (w *gatedWriter) Write(p []byte) (int, error) {
+	w.started <- struct{}{}
+	<-w.release
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.records = append(w.records, string(p))
+
+	return len(p), nil
+}
+
+func (w *gatedWriter) Close() error {
+	return nil
+}
+
+func (w *gatedWriter) Records() string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	return strings.Join(w.records, "")
+}
+
+// fill writes the first record and waits until the background goroutine
+// gets stuck on it, then fills the queue of the given size.
+func fill(t *testing.T, a io.Writer, gated *gatedWriter, size int) {
+	t.Helper()
+
+	_, err := a.Write([]byte("0"))
+	require.NoError(t, err)
+
+	<-gated.started
+
+	for i := 1; i <= size; i++ {
+		_, err := fmt.Fprint(a, i)
+		require.NoError(t, err)
+	}
+}
+
+func Test_Async_DropNewest(t *testing.T) {
+	require := require.New(t)
+
+	gated := newGatedWriter()
+	a := outputs.NewAsync(gated, 2, outputs.OverflowDropNewest)
+
+	fill(t, a, gated, 2)
+
+	_, err := a.Write([]byte("3"))
+	require.NoError(err)
+	require.Equal(uint64(1), outputs.Dropped(a))
+
+	close(gated.release)
+	require.NoError(a.Close())
+	require.Equal("012", gated.Records())
+}
+
+func Test_Async_DropOldest(t *testing.T) {
+	require := require.New(t)
+
+	gated := newGatedWriter()
+	a := outputs.NewAsync(gated, 2, outputs.OverflowDropOldest)
+
+	fill(t, a, gated, 2)
+
+	_, err := a.Write([]byte("3"))
+	require.NoError(err)
+	require.Equal(uint64(1), outputs.Dropped(a))
+
+	close(gated.release)
+	require.NoError(a.Close())
+	require.Equal("023", gated.Records())
+}
+
+func Test_Async_Block(t *testing.T) {
+	require := require.New(t)
+
+	gated := newGatedWriter()
+	a := outputs.NewAsync(gated, 2, outputs.OverflowBlock)
+
+	fill(t, a, gated, 2)
+
+	written := make(chan struct{})
+
+	go func() {
+		defer close(written)
+
+		_, _ = a.Write([]byte("3"))
+	}()
+
+	select {
+	case <-written:
+		require.Fail("write must block on a full queue")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	close(gated.release)
+	<-written
+
+	require.NoError(a.Close())
+	require.Equal("0123", gated.Records())
+	require.Zero(outputs.Dropped(a))
+}
+
+func Test_Async_WriteAfterClose(t *testing.T) {
+	require := require.New(t)
+
+	gated := newGatedWriter()
+	close(gated.release)
+
+	a := outputs.NewAsync(gated, 2, outputs.OverflowBlock)
+	require.NoError(a.Close())
+	require.NoError(a.Close())
+
+	_, err := a.Write([]byte("0"))
+	require.ErrorIs(err, os.ErrClosed)
+}
+
+func Test_Outputs_Async_CloseDrains(t *testing.T) {
+	require := require.New(t)
+
+	filename := filepath.Join(t.TempDir(), "app.log")
+
+	outputs, err := outputs.New(filename, outputs.Opts{QueueSize: 16})
+	require.NoError(err)
+
+	for i := range 1000 {
+		_, err := fmt.Fprintf(outputs, "line=%d\n", i)
+		require.NoError(err)
+	}
+
+	require.NoError(outputs.Close())
+	require.Len(readLines(t, filename), 1000)
+}
@@ -0,0 +1,261 @@
+package outputs
+
+import (
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const gzipExt = ".gz"
+
+// cleaner compresses and removes backups of a file output in
+// a background goroutine, so rotation never waits for it.
+type cleaner struct {
+	// pattern is the file output pattern, see file.pattern.
+	pattern string
+	// current returns the path of the file which is being written.
+	current func() string
+	opts    Opts
+
+	wake chan struct{}
+	done chan struct{}
+	wg   sync.WaitGroup
+}
+
+func newCleaner(pattern string, current func() string, opts Opts) *cleaner {
+	c := &cleaner{
+		pattern: pattern,
+		current: current,
+		opts:    opts,
+		wake:    make(chan struct{}, 1),
+		done:    make(chan struct{}),
+	}
+
+	c.wg.Add(1)
+
+	go c.run()
+
+	return c
+}
+
+// cleanupEnabled reports whether opts require any cleanup of backups.
+func (opts Opts) cleanupEnabled() bool {
+	return opts.MaxBackups > 0 || opts.MaxAge > 0 || opts.Compress
+}
+
+// Trigger schedules a cleanup. It never blocks.
+func (c *cleaner) Trigger() {
+	select {
+	case c.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Close stops the cleaner, waiting for running and scheduled cleanups
+// to finish.
+func (c *cleaner) Close() {
+	close(c.done)
+	c.wg.Wait()
+}
+
+func (c *cleaner) run() {
+	defer c.wg.Done()
+
+	for {
+		select {
+		case <-c.done:
+			// Finish a cleanup scheduled by the last rotation.
+			select {
+			case <-c.wake:
+				c.cleanup()
+			default:
+			}
+
+			return
+		case <-c.wake:
+			c.cleanup()
+		}
+	}
+}
+
+func (c *cleaner) cleanup() {
+	backups, err := listBackups(c.pattern, c.current())
+	if err != nil {
+		c.report(c.pattern, fmt.Errorf("failed to list backups: %w", err))
+
+		return
+	}
+
+	// Newest first.
+	slices.SortFunc(backups, func(a, b backup) int {
+		return b.modTime.Compare(a.modTime)
+	})
+
+	var minTime time.Time
+	if c.opts.MaxAge > 0 {
+		minTime = c.opts.now().Add(-c.opts.MaxAge)
+	}
+
+	for i, b := range backups {
+		expired := c.opts.MaxBackups > 0 && i >= c.opts.MaxBackups ||
+			!minTime.IsZero() && b.modTime.Before(minTime)
+
+		switch {
+		case expired:
+			if err := os.Remove(b.path); err != nil {
+				c.report(b.path, fmt.Errorf("failed to remove backup: %w", err))
+			}
+		case c.opts.Compress && !strings.HasSuffix(b.path, gzipExt):
+			if err := compress(b.path, b.modTime); err != nil {
+				c.report(b.path, fmt.Errorf("failed to compress backup: %w", err))
+			}
+		}
+	}
+}
+
+func (c *cleaner) report(path string, err error) {
+	if c.opts.ErrorHandler != nil {
+		c.opts.ErrorHandler(path, err)
+	}
+}
+
+type backup struct {
+	path    string
+	modTime time.Time
+}
+
+// listBackups returns backups of a file output: numbered backups and
+// files of previous periods, compressed or not. The current file
+// is skipped.
+func listBackups(pattern, current string) ([]backup, error) {
+	dir := filepath.Dir(pattern)
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	glob := patternGlob(filepath.Base(pattern))
+	current = filepath.Clean(current)
+	backups := make([]backup, 0, len(entries))
+
+	for _, entry := range entries {
+		if !entry.Type().IsRegular() {
+			continue
+		}
+
+		path := filepath.Join(dir, entry.Name())
+		if path == current || !isBackup(glob, entry.Name()) {
+			continue
+		}
+
+		info, err := entry.Info()
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+
+		backups = append(backups, backup{path: path, modTime: info.ModTime()})
+	}
+
+	return backups, nil
+}
+
+// isBackup reports whether name is glob optionally followed by
+// a backup number and a compression extension.
+func isBackup(glob, name string) bool {
+	name = strings.TrimSuffix(name, gzipExt)
+
+	if matched, _ := filepath.Match(glob, name); matched {
+		return true
+	}
+
+	i := strings.LastIndexByte(name, '.')
+	if i < 0 {
+		return false
+	}
+
+	if _, err := strconv.Atoi(name[i+1:]); err != nil {
+		return false
+	}
+
+	matched, _ := filepath.Match(glob, name[:i])
+
+	return matched
+}
+
+// patternGlob replaces strftime verbs of the pattern with wildcards
+// and escapes glob metacharacters.
+func patternGlob(pattern string) string {
+	var b strings.Builder
+
+	for i := 0; i < len(pattern); i++ {
+		switch c := pattern[i]; c {
+		case '%':
+			if i+1 < len(pattern) && pattern[i+1] == '%' {
+				b.WriteByte('%')
+			} else {
+				b.WriteByte('*')
+			}
+
+			i++
+		case '*', '?', '[', '\\':
+			b.WriteByte('\\')
+			b.WriteByte(c)
+		default:
+			b.WriteByte(c)
+		}
+	}
+
+	return b.String()
+}
+
+// compress replaces path with its gzip-compressed copy, keeping
+// the modification time so that retention by age keeps working.
+func compress(path string, modTime time.Time) (err error) {
+	src, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = src.Close() }()
+
+	tmp := path + gzipExt + ".tmp"
+
+	dst, err := os.OpenFile(tmp, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, fs.FileMode(defaultFilePerms))
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		if err != nil {
+			_ = os.Remove(tmp)
+		}
+	}()
+
+	zw := gzip.NewWriter(dst)
+
+	_, err = io.Copy(zw, src)
+	if err = errors.Join(err, zw.Close(), dst.Close()); err != nil {
+		return err
+	}
+
+	if err = os.Chtimes(tmp, modTime, modTime); err != nil {
+		return err
+	}
+
+	if err = os.Rename(tmp, path+gzipExt); err != nil {
+		return err
+	}
+
+	return os.Remove(path)
+}
@@ -0,0 +1,56 @@
+package outputs
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// errorReportInterval is the minimum interval between reports of write
+// failures of a single output.
+const errorReportInterval = time.Second
+
+// errorReporter counts write failures of an output and reports them
+// to a handler at most once per errorReportInterval.
+type errorReporter struct {
+	path    string
+	handler func(output string, err error)
+	now     func() time.Time
+	errors  atomic.Uint64
+
+	mu sync.Mutex
+	// next is the time after which the next failure is reported.
+	next time.Time
+	// suppressed is the number of failures since the last report.
+	suppressed uint64
+}
+
+func (r *errorReporter) fail(err error) {
+	r.errors.Add(1)
+
+	if r.handler == nil {
+		return
+	}
+
+	r.mu.Lock()
+
+	now := r.now()
+	if now.Before(r.next) {
+		r.suppressed++
+		r.mu.Unlock()
+
+		return
+	}
+
+	suppressed := r.suppressed
+	r.suppressed = 0
+	r.next = now.Add(errorReportInterval)
+	r.mu.Unlock()
+
+	if suppressed > 0 {
+		err = fmt.Errorf("%w (%d similar errors suppressed)", err, suppressed)
+	}
+
+	r.handler(r.path, err)
+}
@@ -0,0 +1,137 @@
+package outputs_test
+
+import (
+	"errors"
+	"io"
+	"net/url"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/tarantool/go-tlog/internal/outputs"
+)
+
+var errBroken = errors.New("broken")
+
+// brokenSink fails every write.
+type brokenSink struct{}
+
+func (brokenSink) Write([]byte) (int, error) {
+	return 0, errBroken
+}
+
+func (brokenSink) Close() error {
+	return nil
+}
+
+func init() {
+	err := outputs.RegisterSink("broken", func(*url.URL) (io.WriteCloser, error) {
+		return brokenSink{}, nil
+	})
+	if err != nil {
+		panic(err)
+	}
+}
+
+// handledError is an error passed to Opts.WriteErrorHandler.
+type handledError struct {
+	output string
+	err    error
+}
+
+// errorRecorder records errors passed to Opts.WriteErrorHandler.
+type errorRecorder struct {
+	mu   sync.Mutex
+	errs []handledError
+}
+
+func (r *errorRecorder) Handle(output string, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.errs = append(r.errs, handledError{output: output, err: err})
+}
+
+func (r *errorRecorder) Errors() []handledError {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return append([]handledError(nil), r.errs...)
+}
+
+func Test_Outputs_FailingOutput(t *testing.T) {
+	require := require.New(t)
+
+	dir := t.TempDir()
+	first := filepath.Join(dir, "first.log")
+	last := filepath.Join(dir, "last.log")
+	clock := &fakeClock{now: time.Date(2025, 1, 1, 0, 0, 0, 0, time.Local)}
+	recorder := &errorRecorder{}
+
+	outputs, err := outputs.New(first+",broken://x,"+last, outputs.Opts{
+		WriteErrorHandler: recorder.Handle,
+		Now:               clock.Now,
+	})
+	require.NoError(err)
+
+	for range 3 {
+		n, err := outputs.Write([]byte("message\n"))
+		require.ErrorIs(err, errBroken)
+		require.Equal(len("message\n"), n)
+	}
+
+	clock.Set(clock.Now().Add(time.Second))
+
+	_, err = outputs.Write([]byte("message\n"))
+	require.ErrorIs(err, errBroken)
+	require.NoError(outputs.Close())
+
+	require.Len(readLines(t, first), 4)
+	require.Len(readLines(t, last), 4)
+
+	list := outputs.List()
+	require.Equal(uint64(0), list[0].Errors())
+	require.Equal(uint64(4), list[1].Errors())
+	require.Equal(uint64(0), list[2].Errors())
+
+	errs := recorder.Errors()
+	require.Len(errs, 2)
+	require.Equal("broken://x", errs[0].output)
+	require.Equal(errBroken, errs[0].err)
+	require.Equal("broken://x", errs[1].output)
+	require.ErrorIs(errs[1].err, errBroken)
+	require.EqualError(errs[1].err, "broken (2 similar errors suppressed)")
+}
+
+func Test_Outputs_FailingOutput_Async(t *testing.T) {
+	require := require.New(t)
+
+	dir := t.TempDir()
+	first := filepath.Join(dir, "first.log")
+	last := filepath.Join(dir, "last.log")
+	recorder := &errorRecorder{}
+
+	outputs, err := outputs.New(first+",broken://x,"+last, outputs.Opts{
+		WriteErrorHandler: recorder.Handle,
+		QueueSize:         16,
+	})
+	require.NoError(err)
+
+	for range 3 {
+		_, err := outputs.Write([]byte("message\n"))
+		require.NoError(err)
+	}
+
+	require.NoError(outputs.Close())
+
+	require.Len(readLines(t, first), 3)
+	require.Len(readLines(t, last), 3)
+	require.Equal(uint64(3), outputs.List()[1].Errors())
+
+	errs := recorder.Errors()
+	require.Len(errs, 1)
+	require.Equal(handledError{output: "broken://x", err: errBroken}, errs[0])
+}
@@ -0,0 +1,88 @@
+package outputs
+
+import (
+	"io"
+	"io/fs"
+	"os"
+	"syscall"
+)
+
+// NewAsync exposes asynchronous outputs to tests with custom writers.
+func NewAsync(w io.WriteCloser, size int, overflow Overflow) io.WriteCloser {
+	return newAsync(w, size, overflow, nil)
+}
+
+// Dropped returns the number of records dropped by an output
+// created with NewAsync.
+func Dropped(w io.Writer) uint64 {
+	return w.(*async).Dropped()
+}
+
+// UnregisterSink removes a sink registered by a test, so tests can be
+// run repeatedly.
+func UnregisterSink(scheme string) {
+	sinksMu.Lock()
+	defer sinksMu.Unlock()
+
+	delete(sinks, scheme)
+}
+
+// SetDefaultSyslogSocket replaces /dev/log for the duration of a test.
+func SetDefaultSyslogSocket(t interface{ Cleanup(func()) }, path string) {
+	prev := defaultSyslogSocket
+	defaultSyslogSocket = path
+
+	t.Cleanup(func() {
+		defaultSyslogSocket = prev
+	})
+}
+
+// SetOpenFile replaces opening of files by file outputs for the duration
+// of a test.
+func SetOpenFile(t interface{ Cleanup(func()) }, open func(string, int, fs.FileMode) (*os.File, error)) {
+	prev := openFile
+	openFile = open
+
+	t.Cleanup(func() {
+		openFile = prev
+	})
+}
+
+// NonBlock reports whether f is in non-blocking mode.
+func NonBlock(f *os.File) (bool, error) {
+	rc, err := f.SyscallConn()
+	if err != nil {
+		return false, err
+	}
+
+	var (
+		nonBlock bool
+		nbErr    error
+	)
+
+	if err := rc.Control(func(fd uintptr) {
+		nonBlock, nbErr = isNonBlock(fd)
+	}); err != nil {
+		return false, err
+	}
+
+	return nonBlock, nbErr
+}
+
+// SetBlock puts f in blocking mode, as stdout and stderr usually are.
+func SetBlock(f *os.File) error {
+	rc, err := f.SyscallConn()
+	if err != nil {
+		return err
+	}
+
+	var nbErr error
+
+	if err := rc.Control(func(fd uintptr) {
+		nbErr = syscall.SetNonblock(int(fd), false)
+	}); err != nil {
+		return err
+	}
+
+	return nbErr
+}
@@ -0,0 +1,337 @@
+package outputs
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// file is a file output. It rotates the file to a numbered backup
+// once it grows beyond Opts.MaxSize and switches to a new file at
+// every Opts.Interval boundary.
+type file struct {
+	mu sync.Mutex
+	// pattern is the configured path, it may contain strftime verbs.
+	pattern string
+	// path is the path of the currently opened file.
+	path string
+	opts Opts
+	f    *os.File
+	size int64
+	// regular is false for devices and pipes like /dev/null,
+	// which are never rotated.
+	regular bool
+	// backup is the number of the last backup created for path.
+	backup int
+	// renamed is set if the file has been renamed to a backup, but
+	// a new file could not be opened at path. Records are written to
+	// the backup until it is.
+	renamed bool
+	// periodEnd is the time of the next switch, zero without Interval.
+	periodEnd time.Time
+	// cleaner enforces retention of backups, nil if it is disabled.
+	cleaner *cleaner
+}
+
+func newFile(pattern string, opts Opts) (*file, error) {
+	if isPattern(pattern) && opts.Interval == 0 {
+		return nil, errors.New("file name pattern requires rotation interval")
+	}
+
+	f := &file{
+		pattern: pattern,
+		path:    pattern,
+		opts:    opts,
+	}
+
+	if opts.Interval > 0 {
+		now := opts.now()
+		f.path = strftime(pattern, now)
+		f.periodEnd = periodEnd(now, opts.Interval)
+	}
+
+	if err := f.open(); err != nil {
+		return nil, err
+	}
+
+	if err := f.link(); err != nil {
+		_ = f.f.Close()
+
+		return nil, err
+	}
+
+	if opts.cleanupEnabled() {
+		f.cleaner = newCleaner(pattern, f.currentPath, opts)
+		// Deal with backups left by previous runs.
+		f.cleaner.Trigger()
+	}
+
+	return f, nil
+}
+
+// currentPath returns the path of the file being written, which is the
+// last backup if no new file could be opened after a rotation.
+func (f *file) currentPath() string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.renamed {
+		return backupName(f.path, f.backup)
+	}
+
+	return f.path
+}
+
+// rotated schedules cleanup of backups after a rotation.
+func (f *file) rotated() {
+	if f.cleaner != nil {
+		f.cleaner.Trigger()
+	}
+}
+
+// openFile opens files of file outputs, tests replace it to fail.
+var openFile = os.OpenFile
+
+func (f *file) open() error {
+	file, err := openFile(f.path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, fs.FileMode(defaultFilePerms))
+	if err != nil {
+		return err
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		_ = file.Close()
+
+		return err
+	}
+
+	f.f = file
+	f.size = info.Size()
+	f.regular = info.Mode().IsRegular()
+
+	return nil
+}
+
+// Write writes p to the file, rotating it beforehand if the period is
+// over or p does not fit into MaxSize. A single p is never split between
+// two files. If the rotation fails, p is still written to the current
+// file and the error is reported along with it.
+func (f *file) Write(p []byte) (int, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.f == nil {
+		return 0, os.ErrClosed
+	}
+
+	var rotateErr error
+
+	if !f.periodEnd.IsZero() {
+		if now := f.opts.now(); !now.Before(f.periodEnd) {
+			if err := f.switchPeriod(now); err != nil {
+				rotateErr = fmt.Errorf("failed to rotate %q: %w", f.path, err)
+			}
+		}
+	}
+
+	if rotateErr == nil && f.regular && f.opts.MaxSize > 0 && f.size > 0 &&
+		f.size+int64(len(p)) > f.opts.MaxSize {
+		if err := f.rotate(); err != nil {
+			rotateErr = fmt.Errorf("failed to rotate %q: %w", f.path, err)
+		}
+	}
+
+	n, err := f.f.Write(p)
+	f.size += int64(n)
+
+	return n, errors.Join(rotateErr, err)
+}
+
+// switchPeriod opens the file for the period containing now. If the
+// pattern has no verbs, the current file is rotated to a numbered backup
+// instead. The caller must hold f.mu.
+func (f *file) switchPeriod(now time.Time) error {
+	f.periodEnd = periodEnd(now, f.opts.Interval)
+
+	path := strftime(f.pattern, now)
+	if path == f.path {
+		if !f.regular || f.size == 0 {
+			return nil
+		}
+
+		return f.rotate()
+	}
+
+	old, oldPath := f.f, f.path
+
+	f.path = path
+	if err := f.open(); err != nil {
+		f.path = oldPath
+
+		return err
+	}
+
+	f.backup = 0
+	f.rotated()
+
+	return errors.Join(old.Close(), f.link())
+}
+
+// rotate renames the current file to the next numbered backup and
+// opens a fresh file at path. If the file has already been renamed,
+// only the opening is retried. The caller must hold f.mu.
+func (f *file) rotate() error {
+	if !f.renamed {
+		if f.backup == 0 {
+			f.backup = lastBackup(f.path)
+		}
+
+		backup := backupName(f.path, f.backup+1)
+
+		// Rename before closing, so the old descriptor stays usable
+		// if anything below fails.
+		if err := os.Rename(f.path, backup); err != nil {
+			return err
+		}
+
+		f.backup++
+		f.renamed = true
+	}
+
+	old := f.f
+	if err := f.open(); err != nil {
+		return err
+	}
+
+	// The backup is complete only now, records are written to it until
+	// the new file is opened.
+	f.renamed = false
+	f.rotated()
+
+	return old.Close()
+}
+
+// Reopen closes the file and opens it again at the same path. It is
+// used when the file was moved away by an external tool like logrotate.
+func (f *file) Reopen() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.f == nil {
+		return os.ErrClosed
+	}
+
+	old := f.f
+	if err := f.open(); err != nil {
+		return err
+	}
+
+	f.backup = 0
+	f.renamed = false
+
+	return old.Close()
+}
+
+// Sync commits the current file to stable storage. Devices and pipes
+// are not synced.
+func (f *file) Sync() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.f == nil {
+		return os.ErrClosed
+	}
+
+	if !f.regular {
+		return nil
+	}
+
+	return f.f.Sync()
+}
+
+// link points Opts.Symlink at the current file. The link is replaced
+// atomically, so it never dangles.
+func (f *file) link() error {
+	if f.opts.Symlink == "" {
+		return nil
+	}
+
+	target := f.path
+	if filepath.Dir(target) == filepath.Dir(f.opts.Symlink) {
+		target = filepath.Base(target)
+	}
+
+	tmp := f.opts.Symlink + ".tmp"
+	_ = os.Remove(tmp)
+
+	if err := os.Symlink(target, tmp); err != nil {
+		return fmt.Errorf("failed to create symlink: %w", err)
+	}
+
+	if err := os.Rename(tmp, f.opts.Symlink); err != nil {
+		_ = os.Remove(tmp)
+
+		return fmt.Errorf("failed to create symlink: %w", err)
+	}
+
+	return nil
+}
+
+// Close closes the file. Further writes fail with os.ErrClosed.
+func (f *file) Close() error {
+	// The cleaner takes f.mu, so stop it first.
+	if f.cleaner != nil {
+		f.cleaner.Close()
+		f.cleaner = nil
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.f == nil {
+		return nil
+	}
+
+	err := f.f.Close()
+	f.f = nil
+
+	return err
+}
+
+// backupName returns the name of the n-th backup of path.
+// Backups are numbered in ascending order, so the highest number
+// is the most recent one.
+func backupName(path string, n int) string {
+	return path + "." + strconv.Itoa(n)
+}
+
+// lastBackup returns the highest backup number that exists for path.
+func lastBackup(path string) int {
+	entries, err := os.ReadDir(filepath.Dir(path))
+	if err != nil {
+		return 0
+	}
+
+	prefix := filepath.Base(path) + "."
+	last := 0
+
+	for _, entry := range entries {
+		suffix, ok := strings.CutPrefix(strings.TrimSuffix(entry.Name(), gzipExt), prefix)
+		if !ok {
+			continue
+		}
+
+		n, err := strconv.Atoi(suffix)
+		if err == nil && n > last {
+			last = n
+		}
+	}
+
+	return last
+}
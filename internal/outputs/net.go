@@ -0,0 +1,411 @@
+package outputs
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	// defaultBacklog is the default number of records kept while
+	// the peer is unavailable.
+	defaultBacklog = 1024
+
+	minBackoff = 100 * time.Millisecond
+	// defaultMaxBackoff is the default maximum delay between
+	// reconnection attempts.
+	defaultMaxBackoff = 10 * time.Second
+
+	netTimeout = 5 * time.Second
+)
+
+// parseNet parses "tcp://host:port" and "unix:///path" destinations.
+func parseNet(u *url.URL) (network, address string, err error) {
+	switch u.Scheme {
+	case "tcp":
+		if u.Host == "" || u.Path != "" {
+			return "", "", errors.New("tcp destination must be tcp://host:port")
+		}
+
+		return "tcp", u.Host, nil
+	default:
+		if u.Host != "" || u.Path == "" {
+			return "", "", errors.New("unix destination must be unix:///path")
+		}
+
+		return "unix", u.Path, nil
+	}
+}
+
+// netRecord is a record of the backlog or of the spool.
+type netRecord struct {
+	seq uint64
+	p   []byte
+
+	spooled bool
+	pos     spoolPos
+}
+
+// netWriter writes records to a stream socket from a background
+// goroutine. While the peer is unavailable, records are kept in
+// a bounded backlog and the connection is retried with exponential
+// backoff, so Write never blocks on the network.
+//
+// With a spool, records are appended to it instead while the peer is
+// unavailable, the backlog is full or the spool still has records, so
+// they are sent in the order they were written.
+type netWriter struct {
+	network string
+	address string
+	backlog int
+
+	mu      sync.Mutex
+	records []netRecord
+	// seq is the sequence number of the last appended record.
+	seq     uint64
+	closed  bool
+	dropped atomic.Uint64
+	// spool is nil without Opts.SpoolDir.
+	spool *spool
+	// connected is true while the peer is connected.
+	connected bool
+
+	maxBackoff time.Duration
+
+	wake chan struct{}
+	done chan struct{}
+	// stopped is closed once the background goroutine exits.
+	stopped chan struct{}
+}
+
+func newNet(u *url.URL, opts Opts) (*netWriter, error) {
+	network, address, err := parseNet(u)
+	if err != nil {
+		return nil, err
+	}
+
+	w := &netWriter{
+		network:    network,
+		address:    address,
+		backlog:    opts.Backlog,
+		maxBackoff: opts.MaxBackoff,
+		wake:       make(chan struct{}, 1),
+		done:       make(chan struct{}),
+		stopped:    make(chan struct{}),
+	}
+
+	if w.backlog <= 0 {
+		w.backlog = defaultBacklog
+	}
+
+	if w.maxBackoff <= 0 {
+		w.maxBackoff = defaultMaxBackoff
+	}
+
+	if opts.SpoolDir != "" {
+		dir := filepath.Join(opts.SpoolDir, spoolName(network, address))
+		if w.spool, err = newSpool(dir, opts.SpoolMaxSize); err != nil {
+			return nil, err
+		}
+	}
+
+	go w.run()
+
+	return w, nil
+}
+
+// spoolName returns the name of the spool directory of a destination,
+// so that destinations can share Opts.SpoolDir.
+func spoolName(network, address string) string {
+	return network + "_" + strings.NewReplacer("/", "_", ":", "_").Replace(address)
+}
+
+// Write appends a copy of p to the spool or to the backlog. If the backlog
+// is full, the oldest record is dropped. It never returns a network error.
+func (w *netWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+
+	if w.closed {
+		w.mu.Unlock()
+
+		return 0, os.ErrClosed
+	}
+
+	var spoolErr error
+
+	if w.spool != nil && (!w.connected || !w.spool.empty() || len(w.records) >= w.backlog) {
+		if spoolErr = w.spoolBacklog(); spoolErr == nil {
+			spoolErr = w.spool.append(p)
+		}
+
+		if spoolErr == nil {
+			w.mu.Unlock()
+			w.notify()
+
+			return len(p), nil
+		}
+
+		// Keep the record in memory at least.
+		spoolErr = fmt.Errorf("failed to spool record: %w", spoolErr)
+	}
+
+	if len(w.records) >= w.backlog {
+		w.records[0] = netRecord{}
+		w.records = w.records[1:]
+		w.dropped.Add(1)
+	}
+
+	w.seq++
+	w.records = append(w.records, netRecord{seq: w.seq, p: bytes.Clone(p)})
+	w.mu.Unlock()
+	w.notify()
+
+	return len(p), spoolErr
+}
+
+// notify wakes up the background goroutine.
+func (w *netWriter) notify() {
+	select {
+	case w.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Dropped returns the number of records dropped due to the full backlog
+// or the full spool.
+func (w *netWriter) Dropped() uint64 {
+	dropped := w.dropped.Load()
+
+	if w.spool != nil {
+		w.mu.Lock()
+		dropped += w.spool.dropped
+		w.mu.Unlock()
+	}
+
+	return dropped
+}
+
+// setConnected marks the peer as connected or not. Once the peer is
+// gone, the backlog is moved to the spool ahead of newer records.
+func (w *netWriter) setConnected(connected bool) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.connected = connected
+
+	if !connected && w.spool != nil {
+		// Records left in the backlog are sent first anyway.
+		_ = w.spoolBacklog()
+	}
+}
+
+// spoolBacklog moves the backlog to the spool, so that the spool holds
+// the oldest records. The records which could not be spooled are kept
+// in the backlog. w.mu must be held.
+func (w *netWriter) spoolBacklog() error {
+	for len(w.records) > 0 {
+		if err := w.spool.append(w.records[0].p); err != nil {
+			return err
+		}
+
+		w.records[0] = netRecord{}
+		w.records = w.records[1:]
+	}
+
+	return nil
+}
+
+// peek returns the oldest record. Records of the backlog are older than
+// records of the spool.
+func (w *netWriter) peek() (netRecord, bool) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if len(w.records) > 0 {
+		return w.records[0], true
+	}
+
+	// The spool is left to the next run on Close.
+	if w.spool == nil || w.closed {
+		return netRecord{}, false
+	}
+
+	p, pos, ok := w.spool.peek()
+
+	return netRecord{p: p, spooled: true, pos: pos}, ok
+}
+
+// pop removes the record r from the head of the backlog or the spool,
+// unless Write has dropped it in the meantime.
+func (w *netWriter) pop(r netRecord) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if r.spooled {
+		w.spool.pop(r.pos)
+
+		return
+	}
+
+	if len(w.records) > 0 && w.records[0].seq == r.seq {
+		w.records[0] = netRecord{}
+		w.records = w.records[1:]
+	}
+}
+
+func (w *netWriter) dial() (net.Conn, error) {
+	dialer := net.Dialer{Timeout: netTimeout}
+
+	conn, err := dialer.Dial(w.network, w.address)
+	if err != nil {
+		return nil, err
+	}
+
+	// A write to a connection closed by the peer may succeed, so watch
+	// for the end of the stream and fail further writes as soon as
+	// possible to keep the records in the backlog.
+	go func() {
+		_, _ = io.Copy(io.Discard, conn)
+		_ = conn.Close()
+	}()
+
+	return conn, nil
+}
+
+// send writes the backlog to conn until it is empty. It returns
+// the number of records sent.
+func (w *netWriter) send(conn net.Conn) (int, error) {
+	sent := 0
+
+	for r, ok := w.peek(); ok; r, ok = w.peek() {
+		if err := conn.SetWriteDeadline(time.Now().Add(netTimeout)); err != nil {
+			return sent, err
+		}
+
+		if _, err := conn.Write(r.p); err != nil {
+			return sent, err
+		}
+
+		w.pop(r)
+		sent++
+	}
+
+	return sent, nil
+}
+
+func (w *netWriter) run() {
+	defer close(w.stopped)
+
+	var conn net.Conn
+
+	defer func() {
+		if conn != nil {
+			_ = conn.Close()
+		}
+
+		if w.spool != nil {
+			w.saveBacklog()
+		}
+	}()
+
+	backoff := minBackoff
+
+	for {
+		if conn == nil {
+			var err error
+
+			if conn, err = w.dial(); err != nil {
+				conn = nil
+
+				select {
+				case <-w.done:
+					return
+				case <-time.After(backoff):
+				}
+
+				backoff = min(2*backoff, w.maxBackoff)
+
+				continue
+			}
+
+			w.setConnected(true)
+		}
+
+		sent, err := w.send(conn)
+		// A peer accepting connections, but dropping them right away,
+		// is backed off as well as an unavailable one.
+		if sent > 0 {
+			backoff = minBackoff
+		}
+
+		if err != nil {
+			_ = conn.Close()
+			conn = nil
+
+			w.setConnected(false)
+
+			select {
+			case <-w.done:
+				return
+			case <-time.After(backoff):
+			}
+
+			backoff = min(2*backoff, w.maxBackoff)
+
+			continue
+		}
+
+		select {
+		case <-w.done:
+			// Records written right before Close.
+			_, _ = w.send(conn)
+
+			return
+		case <-w.wake:
+		}
+	}
+}
+
+// saveBacklog moves the records left in the backlog to the spool and
+// closes it, so they are sent by the next run.
+func (w *netWriter) saveBacklog() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if err := w.spoolBacklog(); err != nil {
+		w.dropped.Add(uint64(len(w.records)))
+	}
+
+	w.records = nil
+	w.spool.close()
+}
+
+// Close stops the background goroutine. Records of the backlog are sent
+// out if the peer is connected, otherwise they are discarded or, with
+// a spool, left to be sent by the next run along with the spool.
+func (w *netWriter) Close() error {
+	w.mu.Lock()
+
+	if w.closed {
+		w.mu.Unlock()
+
+		return nil
+	}
+
+	w.closed = true
+	w.mu.Unlock()
+
+	close(w.done)
+	<-w.stopped
+
+	return nil
+}
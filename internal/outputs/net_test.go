@@ -0,0 +1,243 @@
+package outputs_test
+
+import (
+	"bufio"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/tarantool/go-tlog/internal/outputs"
+)
+
+// readNetLines accepts a connection and reads n lines from it.
+func readNetLines(t *testing.T, l net.Listener, n int) []string {
+	t.Helper()
+
+	conn, err := l.Accept()
+	require.NoError(t, err)
+
+	t.Cleanup(func() {
+		_ = conn.Close()
+	})
+
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(5*time.Second)))
+
+	scanner := bufio.NewScanner(conn)
+	lines := make([]string, 0, n)
+
+	for len(lines) < n && scanner.Scan() {
+		lines = append(lines, scanner.Text())
+	}
+
+	require.NoError(t, scanner.Err())
+
+	return lines
+}
+
+func write(t *testing.T, w interface{ Write([]byte) (int, error) }, lines ...string) {
+	t.Helper()
+
+	for _, line := range lines {
+		_, err := w.Write([]byte(line + "\n"))
+		require.NoError(t, err)
+	}
+}
+
+func Test_Outputs_TCP(t *testing.T) {
+	require := require.New(t)
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(err)
+
+	defer func() {
+		_ = l.Close()
+	}()
+
+	outputs, err := outputs.New("tcp://"+l.Addr().String(), outputs.Opts{})
+	require.NoError(err)
+
+	write(t, outputs, "first", "second")
+	require.Equal([]string{"first", "second"}, readNetLines(t, l, 2))
+
+	require.NoError(outputs.Close())
+
+	_, err = outputs.Write([]byte("third\n"))
+	require.ErrorIs(err, os.ErrClosed)
+}
+
+func Test_Outputs_Unix(t *testing.T) {
+	require := require.New(t)
+
+	// Socket paths are limited to ~100 bytes, t.TempDir may be longer.
+	dir, err := os.MkdirTemp("", "tlog")
+	require.NoError(err)
+
+	defer func() {
+		_ = os.RemoveAll(dir)
+	}()
+
+	socket := filepath.Join(dir, "log.sock")
+
+	l, err := net.Listen("unix", socket)
+	require.NoError(err)
+
+	defer func() {
+		_ = l.Close()
+	}()
+
+	outputs, err := outputs.New("unix://"+socket, outputs.Opts{})
+	require.NoError(err)
+
+	defer func() {
+		_ = outputs.Close()
+	}()
+
+	write(t, outputs, "first")
+	require.Equal([]string{"first"}, readNetLines(t, l, 1))
+}
+
+func Test_Outputs_TCP_Reconnect(t *testing.T) {
+	require := require.New(t)
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(err)
+
+	addr := l.Addr().String()
+
+	outputs, err := outputs.New("tcp://"+addr, outputs.Opts{MaxBackoff: 50 * time.Millisecond})
+	require.NoError(err)
+
+	defer func() {
+		_ = outputs.Close()
+	}()
+
+	conn, err := l.Accept()
+	require.NoError(err)
+
+	// The collector goes away.
+	require.NoError(conn.Close())
+	require.NoError(l.Close())
+
+	// Let the output notice the closed connection.
+	time.Sleep(100 * time.Millisecond)
+
+	write(t, outputs, "first", "second")
+
+	// The collector comes back.
+	l, err = net.Listen("tcp", addr)
+	require.NoError(err)
+
+	defer func() {
+		_ = l.Close()
+	}()
+
+	require.Equal([]string{"first", "second"}, readNetLines(t, l, 2))
+}
+
+func Test_Outputs_TCP_SendBackoff(t *testing.T) {
+	require := require.New(t)
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(err)
+
+	defer func() {
+		_ = l.Close()
+	}()
+
+	outputs, err := outputs.New("tcp://"+l.Addr().String(), outputs.Opts{})
+	require.NoError(err)
+
+	defer func() {
+		_ = outputs.Close()
+	}()
+
+	conn, err := l.Accept()
+	require.NoError(err)
+
+	// The collector drops the connection, but keeps listening.
+	require.NoError(conn.Close())
+
+	// Let the output notice the closed connection.
+	time.Sleep(100 * time.Millisecond)
+
+	start := time.Now()
+
+	write(t, outputs, "first")
+	require.Equal([]string{"first"}, readNetLines(t, l, 1))
+
+	// The output reconnects after a delay rather than right after
+	// the failed send.
+	require.GreaterOrEqual(time.Since(start), 100*time.Millisecond)
+}
+
+func Test_Outputs_TCP_Backlog(t *testing.T) {
+	require := require.New(t)
+
+	// Reserve an address with nobody listening on it.
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(err)
+
+	addr := l.Addr().String()
+	require.NoError(l.Close())
+
+	outputs, err := outputs.New("tcp://"+addr, outputs.Opts{Backlog: 2, MaxBackoff: 50 * time.Millisecond})
+	require.NoError(err)
+
+	defer func() {
+		_ = outputs.Close()
+	}()
+
+	require.True(outputs.MayDrop())
+
+	write(t, outputs, "1", "2", "3", "4", "5")
+	require.Equal(uint64(3), outputs.Dropped())
+
+	l, err = net.Listen("tcp", addr)
+	require.NoError(err)
+
+	defer func() {
+		_ = l.Close()
+	}()
+
+	require.Equal([]string{"4", "5"}, readNetLines(t, l, 2))
+}
+
+func Test_Outputs_TCP_CloseUnavailable(t *testing.T) {
+	require := require.New(t)
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(err)
+
+	addr := l.Addr().String()
+	require.NoError(l.Close())
+
+	outputs, err := outputs.New("tcp://"+addr, outputs.Opts{})
+	require.NoError(err)
+
+	write(t, outputs, "lost")
+
+	start := time.Now()
+
+	require.NoError(outputs.Close())
+	require.Less(time.Since(start), time.Second)
+}
+
+func Test_New_BadNet(t *testing.T) {
+	for _, tc := range []struct {
+		path string
+		err  string
+	}{
+		{"tcp://", "tcp destination must be tcp://host:port"},
+		{"tcp://localhost:80/path", "tcp destination must be tcp://host:port"},
+		{"unix://host/path", "unix destination must be unix:///path"},
+	} {
+		t.Run(tc.path, func(t *testing.T) {
+			_, err := outputs.New(tc.path, outputs.Opts{})
+			require.ErrorContains(t, err, tc.err)
+		})
+	}
+}
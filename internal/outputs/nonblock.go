@@ -0,0 +1,189 @@
+package outputs
+
+import (
+	"errors"
+	"os"
+	"sync"
+	"sync/atomic"
+	"syscall"
+)
+
+// errWouldBlock is returned by writeNonBlock instead of waiting.
+var errWouldBlock = errors.New("write would block")
+
+// writeNonBlock writes p with a single write(2) to the descriptor of rc,
+// which must be in non-blocking mode. It returns errWouldBlock instead
+// of waiting for the descriptor to become writable. Writes of up to
+// PIPE_BUF bytes to a pipe are never partial.
+func writeNonBlock(rc syscall.RawConn, p []byte) (int, error) {
+	var (
+		n   int
+		err error
+	)
+
+	ctlErr := rc.Write(func(fd uintptr) bool {
+		n, err = syscall.Write(int(fd), p)
+
+		// Never wait in the runtime poller.
+		return true
+	})
+	if ctlErr != nil {
+		return 0, ctlErr
+	}
+
+	if errors.Is(err, syscall.EAGAIN) {
+		return max(n, 0), errWouldBlock
+	}
+
+	if err != nil {
+		return max(n, 0), err
+	}
+
+	if n < len(p) {
+		return n, errWouldBlock
+	}
+
+	return n, nil
+}
+
+// nonBlockStream writes records to a stream in non-blocking mode,
+// dropping records which would block. A record of which only a part
+// fits is not written further, but the next record written starts with
+// a newline, so that records are never glued together. Writes must be
+// serialized by the caller.
+type nonBlockStream struct {
+	// torn is set if the last record has been written partially.
+	torn    bool
+	dropped atomic.Uint64
+}
+
+// write writes p to the descriptor of rc. It reports p as written
+// unless the write fails for another reason than blocking.
+func (s *nonBlockStream) write(rc syscall.RawConn, p []byte) (int, error) {
+	b := p
+	if s.torn {
+		b = append([]byte{'\n'}, p...)
+	}
+
+	n, err := writeNonBlock(rc, b)
+	if s.torn && n > 0 {
+		s.torn = false
+	}
+
+	written := max(n-(len(b)-len(p)), 0)
+
+	switch {
+	case err == nil:
+		return len(p), nil
+	case errors.Is(err, errWouldBlock) && written == 0:
+		s.dropped.Add(1)
+
+		return len(p), nil
+	case errors.Is(err, errWouldBlock):
+		s.torn = true
+
+		return len(p), nil
+	default:
+		return written, err
+	}
+}
+
+// Dropped returns the number of records which would block.
+func (s *nonBlockStream) Dropped() uint64 {
+	return s.dropped.Load()
+}
+
+// rawConn returns the raw descriptor of w, if it has one.
+func rawConn(w any) (syscall.RawConn, error) {
+	sc, ok := w.(syscall.Conn)
+	if !ok {
+		return nil, errors.New("non-blocking mode is not supported")
+	}
+
+	return sc.SyscallConn()
+}
+
+// stdNonBlock writes to stdout or stderr in non-blocking mode, dropping
+// records which would block.
+type stdNonBlock struct {
+	// f keeps the descriptor of rc open.
+	f  *os.File
+	rc syscall.RawConn
+	// wasBlocking is set if f has been in blocking mode before, it is
+	// restored on Close.
+	wasBlocking bool
+
+	mu     sync.Mutex
+	stream nonBlockStream
+}
+
+// newStdNonBlock puts f in non-blocking mode. It affects the open file
+// description, which is shared with other processes, e.g. the parent
+// shell, so the mode is restored on Close.
+func newStdNonBlock(f *os.File) (*stdNonBlock, error) {
+	rc, err := f.SyscallConn()
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		wasNonBlock bool
+		nbErr       error
+	)
+
+	// f.Fd() would put f back in blocking mode.
+	if err := rc.Control(func(fd uintptr) {
+		if wasNonBlock, nbErr = isNonBlock(fd); nbErr == nil {
+			nbErr = syscall.SetNonblock(int(fd), true)
+		}
+	}); err != nil {
+		return nil, err
+	}
+
+	if nbErr != nil {
+		return nil, nbErr
+	}
+
+	return &stdNonBlock{f: f, rc: rc, wasBlocking: !wasNonBlock}, nil
+}
+
+// isNonBlock reports whether fd is in non-blocking mode.
+func isNonBlock(fd uintptr) (bool, error) {
+	flags, _, errno := syscall.Syscall(syscall.SYS_FCNTL, fd, syscall.F_GETFL, 0)
+	if errno != 0 {
+		return false, errno
+	}
+
+	return flags&syscall.O_NONBLOCK != 0, nil
+}
+
+// Write writes p unless it would block, in which case p is dropped.
+func (w *stdNonBlock) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	return w.stream.write(w.rc, p)
+}
+
+// Dropped returns the number of records which would block.
+func (w *stdNonBlock) Dropped() uint64 {
+	return w.stream.Dropped()
+}
+
+// Close leaves stdout and stderr open, but puts them back in blocking
+// mode if they have been in it before.
+func (w *stdNonBlock) Close() error {
+	if !w.wasBlocking {
+		return nil
+	}
+
+	var nbErr error
+
+	if err := w.rc.Control(func(fd uintptr) {
+		nbErr = syscall.SetNonblock(int(fd), false)
+	}); err != nil {
+		return err
+	}
+
+	return nbErr
+}
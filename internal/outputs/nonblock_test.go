@@ -0,0 +1,203 @@
+package outputs_test
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/tarantool/go-tlog/internal/outputs"
+)
+
+// records is enough records to fill a pipe or a socket buffer.
+const records = 4096
+
+var record = []byte(strings.Repeat("x", 127) + "\n")
+
+// writeAll writes records to outputs and fails if they do not complete,
+// as they would block forever.
+func writeAll(t *testing.T, outputs *outputs.Outputs) {
+	t.Helper()
+
+	done := make(chan error, 1)
+
+	go func() {
+		for range records {
+			if _, err := outputs.Write(record); err != nil {
+				done <- err
+
+				return
+			}
+		}
+
+		done <- nil
+	}()
+
+	select {
+	case err := <-done:
+		require.NoError(t, err)
+	case <-time.After(10 * time.Second):
+		require.FailNow(t, "writes have blocked")
+	}
+}
+
+// Not parallel: replaces os.Stderr.
+func Test_Outputs_NonBlock_Stderr(t *testing.T) {
+	require := require.New(t)
+
+	r, w, err := os.Pipe()
+	require.NoError(err)
+
+	// Nobody reads from r.
+	defer func() {
+		_ = r.Close()
+		_ = w.Close()
+	}()
+
+	stderr := os.Stderr
+	os.Stderr = w
+
+	defer func() {
+		os.Stderr = stderr
+	}()
+
+	outputs, err := outputs.New("stderr", outputs.Opts{NonBlock: true})
+	require.NoError(err)
+	require.True(outputs.MayDrop())
+
+	writeAll(t, outputs)
+
+	require.NotZero(outputs.Dropped())
+	require.Less(outputs.Dropped(), uint64(records))
+	require.NoError(outputs.Close())
+}
+
+// Not parallel: replaces os.Stderr.
+func Test_Outputs_NonBlock_LargeRecords(t *testing.T) {
+	require := require.New(t)
+
+	r, w, err := os.Pipe()
+	require.NoError(err)
+
+	defer func() {
+		_ = r.Close()
+		_ = w.Close()
+	}()
+
+	stderr := os.Stderr
+	os.Stderr = w
+
+	defer func() {
+		os.Stderr = stderr
+	}()
+
+	outputs, err := outputs.New("stderr", outputs.Opts{NonBlock: true})
+	require.NoError(err)
+
+	// Records larger than PIPE_BUF may be written partially.
+	const (
+		count = 20
+		size  = 10 << 10
+	)
+
+	for i := range count {
+		_, err := outputs.Write([]byte(fmt.Sprintf("%03d", i) + strings.Repeat("x", size-4) + "\n"))
+		require.NoError(err)
+	}
+
+	require.NoError(outputs.Close())
+	require.NoError(w.Close())
+
+	data, err := io.ReadAll(r)
+	require.NoError(err)
+
+	lines := strings.Split(strings.TrimSuffix(string(data), "\n"), "\n")
+
+	// Partially written records are cut, but never glued to the next ones.
+	for _, line := range lines {
+		require.Regexp(`^\d{1,3}x*$`, line)
+		require.LessOrEqual(len(line), size-1)
+	}
+
+	require.NotZero(outputs.Dropped())
+	require.Equal(uint64(count), uint64(len(lines))+outputs.Dropped())
+}
+
+// Not parallel: replaces os.Stdout.
+func Test_Outputs_NonBlock_RestoresMode(t *testing.T) {
+	require := require.New(t)
+
+	r, w, err := os.Pipe()
+	require.NoError(err)
+
+	defer func() {
+		_ = r.Close()
+		_ = w.Close()
+	}()
+
+	// Pipes of os.Pipe are non-blocking, unlike stdout of a process.
+	require.NoError(outputs.SetBlock(w))
+
+	stdout := os.Stdout
+	os.Stdout = w
+
+	defer func() {
+		os.Stdout = stdout
+	}()
+
+	out, err := outputs.New("stdout", outputs.Opts{NonBlock: true})
+	require.NoError(err)
+
+	nonBlock, err := outputs.NonBlock(w)
+	require.NoError(err)
+	require.True(nonBlock)
+
+	require.NoError(out.Close())
+
+	nonBlock, err = outputs.NonBlock(w)
+	require.NoError(err)
+	require.False(nonBlock)
+}
+
+func Test_Outputs_NonBlock_Pipe(t *testing.T) {
+	require := require.New(t)
+
+	// The command does not read for a while.
+	outputs, err := outputs.New("pipe:sleep 1; cat > /dev/null", outputs.Opts{NonBlock: true})
+	require.NoError(err)
+	require.True(outputs.MayDrop())
+
+	writeAll(t, outputs)
+
+	require.NotZero(outputs.Dropped())
+	require.NoError(outputs.Close())
+}
+
+func Test_Outputs_NonBlock_Syslog(t *testing.T) {
+	require := require.New(t)
+
+	// Nobody reads from the socket.
+	socket, _ := listenSyslog(t)
+
+	outputs, err := outputs.New("syslog:server="+socket, outputs.Opts{NonBlock: true})
+	require.NoError(err)
+	require.True(outputs.MayDrop())
+
+	writeAll(t, outputs)
+
+	require.NotZero(outputs.Dropped())
+	require.NoError(outputs.Close())
+}
+
+func Test_Outputs_Block_MayDrop(t *testing.T) {
+	require := require.New(t)
+
+	outputs, err := outputs.New("stderr,pipe:cat > /dev/null", outputs.Opts{})
+	require.NoError(err)
+	require.False(outputs.MayDrop())
+	require.NoError(outputs.Close())
+}
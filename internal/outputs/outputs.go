@@ -4,45 +4,239 @@ import (
 	"errors"
 	"fmt"
 	"io"
-	"io/fs"
+	"log/slog"
+	"net/url"
 	"os"
+	"slices"
 	"strings"
+	"sync/atomic"
+	"time"
 )
 
 // Outputs is io.WriteCloser for multiple output paths.
 type Outputs struct {
-	files []*os.File
-	w     io.Writer
+	outputs []*Output
+	// stopSync stops periodic syncing, if any.
+	stopSync func()
+	closed   atomic.Bool
+}
+
+// Output is a single destination of Outputs.
+type Output struct {
+	// Path is the destination as given to New, without modifiers.
+	Path string
+	// Modifiers are trailing ":name" parts of the destination accepted
+	// by Opts.IsModifier, e.g. "error" for "stderr:error".
+	Modifiers []string
+
+	url *url.URL
+	// sink is the destination itself, w may wrap it, e.g. with a queue.
+	sink     io.WriteCloser
+	w        io.WriteCloser
+	reporter errorReporter
+}
+
+// Write writes p to the destination.
+func (o *Output) Write(p []byte) (int, error) {
+	return o.WriteLevel(slog.LevelInfo, p)
+}
+
+// WriteLevel writes p encoded from a record of the given level.
+// Some destinations like syslog depend on the level.
+func (o *Output) WriteLevel(level slog.Level, p []byte) (int, error) {
+	n, err := writeLevel(o.w, level, p)
+	if err != nil {
+		o.reporter.fail(err)
+	}
+
+	return n, err
+}
+
+// Errors returns the number of failed writes to the destination,
+// including failures of its background goroutine, if any.
+func (o *Output) Errors() uint64 {
+	return o.reporter.errors.Load()
+}
+
+// levelWriter is a destination which depends on the level of a record.
+type levelWriter interface {
+	WriteLevel(level slog.Level, p []byte) (int, error)
+}
+
+func writeLevel(w io.Writer, level slog.Level, p []byte) (int, error) {
+	if lw, ok := w.(levelWriter); ok {
+		return lw.WriteLevel(level, p)
+	}
+
+	return w.Write(p)
+}
+
+// Opts are New options.
+type Opts struct {
+	// MaxSize is the size in bytes after which a file output is rotated
+	// to a numbered backup. Zero disables size-based rotation.
+	MaxSize int64
+	// Interval is the period after which a file output is switched to
+	// a new file. Periods are aligned to the local midnight, so Interval
+	// must divide 24 hours. File paths may contain strftime-style verbs
+	// (%Y, %m, %d, %H, ...) which are expanded with the period start.
+	// Zero disables time-based rotation.
+	Interval time.Duration
+	// Symlink is the path of a symbolic link that is kept pointing at
+	// the current file. It requires a single file output.
+	Symlink string
+	// MaxBackups is the maximum number of backups to keep.
+	// Zero keeps all of them.
+	MaxBackups int
+	// MaxAge is the maximum age of backups to keep.
+	// Zero keeps backups of any age.
+	MaxAge time.Duration
+	// Compress enables gzip compression of backups.
+	Compress bool
+	// ErrorHandler is called on failures of background cleanup of
+	// backups. Such failures are ignored if it is nil.
+	ErrorHandler func(path string, err error)
+	// WriteErrorHandler is called with Output.Path on write failures,
+	// at most once per second for each output. Such failures are only
+	// counted if it is nil, see Output.Errors.
+	WriteErrorHandler func(output string, err error)
+	// Sync is the policy of syncing outputs supporting it, like files,
+	// to stable storage.
+	Sync SyncPolicy
+	// SyncLevel is the minimum level of records followed by a sync
+	// with SyncLevel policy.
+	SyncLevel slog.Level
+	// SyncInterval is the period of syncing with SyncInterval policy.
+	SyncInterval time.Duration
+	// QueueSize is the maximum number of records waiting to be written
+	// by the background goroutine of each output. Zero makes outputs
+	// synchronous.
+	QueueSize int
+	// Overflow is the policy for asynchronous outputs with a full queue.
+	Overflow Overflow
+	// Backlog is the maximum number of records kept in memory while
+	// the peer of a network output is unavailable. The oldest records
+	// are dropped once it is full. Default is 1024.
+	Backlog int
+	// MaxBackoff is the maximum delay between attempts to reconnect
+	// a network output. Default is 10 seconds.
+	MaxBackoff time.Duration
+	// SpoolDir is a directory where network outputs keep records while
+	// the peer is unavailable, each in its own subdirectory. Records are
+	// kept in memory only if it is empty.
+	SpoolDir string
+	// SpoolMaxSize is the maximum size of the spool of a network output
+	// in bytes. Once it is reached, the oldest records are dropped.
+	// Default is 64 MiB.
+	SpoolMaxSize int64
+	// NonBlock puts pipe, syslog, stdout and stderr outputs in non-blocking
+	// mode: records which would block are dropped and counted instead.
+	NonBlock bool
+	// IsModifier reports whether name is a known modifier of a path,
+	// see Output.Modifiers. Paths have no modifiers if it is nil.
+	IsModifier func(name string) bool
+	// Now returns the current time. It defaults to time.Now.
+	Now func() time.Time
+}
+
+func (opts Opts) now() time.Time {
+	if opts.Now != nil {
+		return opts.Now()
+	}
+
+	return time.Now()
+}
+
+const day = 24 * time.Hour
+
+func (opts Opts) validate(outputs []*Output) error {
+	if opts.Interval < 0 || opts.Interval > 0 && day%opts.Interval != 0 {
+		return fmt.Errorf("rotation interval %s does not divide 24h", opts.Interval)
+	}
+
+	if opts.Sync == SyncInterval && opts.SyncInterval <= 0 {
+		return fmt.Errorf("sync interval must be positive, got %s", opts.SyncInterval)
+	}
+
+	if opts.Symlink == "" {
+		return nil
+	}
+
+	files := 0
+
+	for _, output := range outputs {
+		if isFile(output.url) {
+			files++
+		}
+	}
+
+	if files != 1 {
+		return errors.New("symlink requires a single file output")
+	}
+
+	return nil
 }
 
 // New creates Outputs from comma-separated string of paths.
-// Use "stdout" and "stderr" for os streams and file paths for files.
-func New(paths string) (*Outputs, error) {
+// Use "stdout" and "stderr" for os streams, "syslog:key=value,..."
+// for syslog, "tcp://host:port" and "unix:///path" for stream sockets,
+// "ring:N" for the last N records kept in memory, "scheme://..." for
+// sinks added with RegisterSink and file paths for files.
+func New(paths string, opts Opts) (*Outputs, error) {
 	if paths == "" {
 		return nil, errors.New("empty paths")
 	}
 
 	slice := splitPaths(paths)
-
-	files := make([]*os.File, 0, len(slice))
-	writers := make([]io.Writer, 0, len(slice))
+	outputs := make([]*Output, 0, len(slice))
 
 	for _, path := range slice {
-		file, err := openFile(path)
-		if err != nil {
-			_ = multiClose(files)
+		path, modifiers := splitModifiers(path, opts.IsModifier)
 
+		u, err := parseURL(path)
+		if err != nil {
 			return nil, fmt.Errorf("failed to open path %q: %w", path, err)
 		}
 
-		files = append(files, file)
-		writers = append(writers, file)
+		output := &Output{Path: path, Modifiers: modifiers, url: u}
+		output.reporter = errorReporter{path: path, handler: opts.WriteErrorHandler, now: opts.now}
+
+		outputs = append(outputs, output)
+	}
+
+	if err := opts.validate(outputs); err != nil {
+		return nil, err
+	}
+
+	for i, output := range outputs {
+		w, err := openSink(output.url, opts)
+		if err != nil {
+			_ = multiClose(outputs[:i])
+
+			return nil, fmt.Errorf("failed to open path %q: %w", output.Path, err)
+		}
+
+		output.sink = w
+
+		if _, ok := w.(syncer); ok && opts.Sync == SyncLevel && !isStd(w) {
+			w = &levelSyncer{w: w, level: opts.SyncLevel}
+		}
+
+		// Rings are in memory, so they gain nothing from a queue.
+		if _, ok := w.(ringer); !ok && opts.QueueSize > 0 {
+			w = newAsync(w, opts.QueueSize, opts.Overflow, output.reporter.fail)
+		}
+
+		output.w = w
+	}
+
+	o := &Outputs{outputs: outputs}
+
+	if opts.Sync == SyncInterval {
+		o.stopSync = o.syncEvery(opts.SyncInterval)
 	}
 
-	return &Outputs{
-		files: files,
-		w:     io.MultiWriter(writers...),
-	}, nil
+	return o, nil
 }
 
 func splitPaths(paths string) []string {
@@ -50,55 +244,197 @@ func splitPaths(paths string) []string {
 		return []string{}
 	}
 
-	split := strings.Split(paths, ",")
+	split := make([]string, 0, strings.Count(paths, ",")+1)
+	entries := strings.Split(paths, ",")
+
+	for i, path := range entries {
+		path = strings.TrimSpace(path)
+
+		// A command may contain commas, e.g. "pipe:cut -d, -f1", so
+		// "pipe:" takes the rest of the paths.
+		if strings.HasPrefix(path, pipePrefix) {
+			split = append(split, strings.TrimSpace(strings.Join(entries[i:], ",")))
+
+			break
+		}
+
+		// Options of "syslog:identity=x,facility=y" contain commas.
+		if last := len(split) - 1; last >= 0 && isSyslogOption(path) &&
+			strings.HasPrefix(split[last], syslogPrefix) {
+			split[last] += "," + path
+
+			continue
+		}
 
-	for i, path := range split {
-		split[i] = strings.TrimSpace(path)
+		split = append(split, path)
 	}
 
 	return split
 }
 
+// prefixes are prefixes of destinations which are not file paths.
+var prefixes = []string{filePrefix, pipePrefix, ringPrefix, syslogPrefix}
+
+// splitModifiers cuts trailing ":name" modifiers from path,
+// e.g. "stderr:error" is split into "stderr" and ["error"].
+func splitModifiers(path string, isModifier func(string) bool) (string, []string) {
+	if isModifier == nil {
+		return path, nil
+	}
+
+	var modifiers []string
+
+	for {
+		i := strings.LastIndexByte(path, ':')
+		if i < 0 || !isModifier(path[i+1:]) {
+			break
+		}
+
+		modifiers = append(modifiers, path[i+1:])
+		path = path[:i]
+
+		// "syslog:error" is "syslog:" with a modifier rather than
+		// a file named "syslog".
+		if slices.Contains(prefixes, path+":") {
+			path += ":"
+
+			break
+		}
+	}
+
+	slices.Reverse(modifiers)
+
+	return path, modifiers
+}
+
 // https://github.com/uber-go/zap/blob/6d482535bdd97f4d97b2f9573ac308f1cf9b574e/sink.go#L158
 var defaultFilePerms uint32 = 0o666
 
-func openFile(path string) (*os.File, error) {
-	switch path {
-	case "stdout":
-		// https://github.com/uber-go/zap/blob/6d482535bdd97f4d97b2f9573ac308f1cf9b574e/sink.go#L153-L154
-		return os.Stdout, nil
-		// https://github.com/uber-go/zap/blob/6d482535bdd97f4d97b2f9573ac308f1cf9b574e/sink.go#L155-L156
-	case "stderr":
-		return os.Stderr, nil
-	case "":
-		return nil, errors.New("empty path")
+func multiClose(outputs []*Output) error {
+	errs := make([]error, 0, len(outputs))
+
+	for _, output := range outputs {
+		errs = append(errs, closeOutput(output.w))
+	}
+
+	return errors.Join(errs...)
+}
+
+// closeOutput closes an output unless it is stdout or stderr.
+func closeOutput(closer io.Closer) error {
+	switch closer {
+	case os.Stdout, os.Stderr, nil:
+		return nil
 	default:
-		return os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, fs.FileMode(defaultFilePerms))
+		return closer.Close()
 	}
 }
 
-func multiClose(files []*os.File) error {
-	errs := make([]error, 0, len(files))
+// List returns the configured output destinations in the order
+// they were given to New.
+func (o *Outputs) List() []*Output {
+	return o.outputs
+}
 
-	for _, file := range files {
-		switch file {
-		case os.Stdout, os.Stderr, nil:
-			continue
-		default:
-			errs = append(errs, file.Close())
+// Write writes p to all configured output destinations. Each of them
+// is written independently, so a failing one does not affect the others.
+// It implements io.Writer regardless of levels of the outputs; records
+// of the logger are written to each output with Output.WriteLevel.
+func (o *Outputs) Write(p []byte) (int, error) {
+	errs := make([]error, 0, len(o.outputs))
+
+	for _, output := range o.outputs {
+		_, err := output.Write(p)
+		errs = append(errs, err)
+	}
+
+	return len(p), errors.Join(errs...)
+}
+
+// reopener is an output that can be reopened at the same path.
+type reopener interface {
+	Reopen() error
+}
+
+// Reopen closes and reopens all file outputs, leaving stdout and stderr
+// alone. Concurrent writes go either to the old or to the new file.
+func (o *Outputs) Reopen() error {
+	errs := make([]error, 0, len(o.outputs))
+
+	for _, output := range o.outputs {
+		if r, ok := output.w.(reopener); ok {
+			errs = append(errs, r.Reopen())
 		}
 	}
 
 	return errors.Join(errs...)
 }
 
-// Write writes p to all configured output destinations.
-// It implements io.Writer and is used by slog handlers.
-func (o *Outputs) Write(p []byte) (int, error) {
-	return o.w.Write(p)
+// dropper is an output which may drop records instead of blocking.
+type dropper interface {
+	Dropped() uint64
+}
+
+// mayDrop reports whether w may drop records.
+func mayDrop(w io.Writer) bool {
+	switch w := w.(type) {
+	case *async:
+		return w.overflow != OverflowBlock
+	case *netWriter, *stdNonBlock:
+		return true
+	case *pipe:
+		return w.opts.NonBlock
+	case *syslogWriter:
+		return w.opts.NonBlock
+	default:
+		return false
+	}
+}
+
+// MayDrop reports whether any of the outputs may drop records.
+func (o *Outputs) MayDrop() bool {
+	for _, output := range o.outputs {
+		if mayDrop(output.w) || mayDrop(output.sink) {
+			return true
+		}
+	}
+
+	return false
 }
 
-// Close closes all file outputs except stdout and stderr.
+// Dropped returns the total number of records dropped by outputs:
+// by asynchronous outputs due to the overflow policy, by network
+// outputs due to the full backlog and by non-blocking outputs.
+func (o *Outputs) Dropped() uint64 {
+	var dropped uint64
+
+	for _, output := range o.outputs {
+		if d, ok := output.w.(dropper); ok {
+			dropped += d.Dropped()
+		}
+
+		if output.sink == output.w {
+			continue
+		}
+
+		if d, ok := output.sink.(dropper); ok {
+			dropped += d.Dropped()
+		}
+	}
+
+	return dropped
+}
+
+// Close syncs the outputs, see Sync, and closes all of them except
+// stdout and stderr. Subsequent calls do nothing.
 func (o *Outputs) Close() error {
-	return multiClose(o.files)
+	if o.closed.Swap(true) {
+		return nil
+	}
+
+	if o.stopSync != nil {
+		o.stopSync()
+	}
+
+	return errors.Join(o.sync(), multiClose(o.outputs))
 }
@@ -1,11 +1,18 @@
 package outputs_test
 
 import (
+	"compress/gzip"
+	"fmt"
 	"io"
+	"io/fs"
 	"os"
 	"path/filepath"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
 	"github.com/tarantool/go-tlog/internal/outputs"
@@ -17,28 +24,28 @@ var _ io.WriteCloser = (*outputs.Outputs)(nil)
 func Test_New_BadPath(t *testing.T) {
 	require := require.New(t)
 
-	_, err := outputs.New("/not/exist")
+	_, err := outputs.New("/not/exist", outputs.Opts{})
 	require.ErrorContains(err, "open /not/exist: no such file or directory")
 }
 
 func Test_New_MultipleWithBadPath(t *testing.T) {
 	require := require.New(t)
 
-	_, err := outputs.New("/dev/null,/not/exist")
+	_, err := outputs.New("/dev/null,/not/exist", outputs.Opts{})
 	require.ErrorContains(err, "open /not/exist: no such file or directory")
 }
 
 func Test_New_EmptyPaths(t *testing.T) {
 	require := require.New(t)
 
-	_, err := outputs.New("")
+	_, err := outputs.New("", outputs.Opts{})
 	require.ErrorContains(err, "empty paths")
 }
 
 func Test_New_MultipleWithEmptyPath(t *testing.T) {
 	require := require.New(t)
 
-	_, err := outputs.New("/dev/null,")
+	_, err := outputs.New("/dev/null,", outputs.Opts{})
 	require.ErrorContains(err, "empty path")
 }
 
@@ -66,7 +73,7 @@ func Test_Outputs_Std(t *testing.T) {
 				}()
 			}
 
-			outputs, err := outputs.New(tc)
+			outputs, err := outputs.New(tc, outputs.Opts{})
 			require.NoError(err)
 
 			_, err = outputs.Write([]byte("log_message"))
@@ -89,7 +96,7 @@ func Test_Outputs_File(t *testing.T) {
 		_ = os.Remove(name)
 	}(filename)
 
-	outputs, err := outputs.New(filename)
+	outputs, err := outputs.New(filename, outputs.Opts{})
 	require.NoError(err)
 
 	_, err = outputs.Write([]byte("log_message"))
@@ -125,7 +132,7 @@ func Test_Outputs_Multiple(t *testing.T) {
 		_ = os.Remove(name)
 	}(filename2)
 
-	outputs, err := outputs.New(filename1 + ",stdout," + filename2)
+	outputs, err := outputs.New(filename1+",stdout,"+filename2, outputs.Opts{})
 	require.NoError(err)
 
 	_, err = outputs.Write([]byte("log_message"))
@@ -148,3 +155,640 @@ func Test_Outputs_Multiple(t *testing.T) {
 	require.NoError(err)
 	require.Contains(string(file2Out), "log_message")
 }
+
+func readLines(t *testing.T, pattern string) []string {
+	t.Helper()
+
+	names, err := filepath.Glob(pattern)
+	require.NoError(t, err)
+
+	lines := []string{}
+
+	for _, name := range names {
+		data, err := os.ReadFile(name)
+		require.NoError(t, err)
+
+		if len(data) == 0 {
+			continue
+		}
+
+		lines = append(lines, strings.Split(strings.TrimSuffix(string(data), "\n"), "\n")...)
+	}
+
+	return lines
+}
+
+func Test_Outputs_RotateBySize(t *testing.T) {
+	require := require.New(t)
+
+	filename := filepath.Join(t.TempDir(), "Test_Outputs_RotateBySize.log")
+
+	outputs, err := outputs.New(filename, outputs.Opts{MaxSize: 10})
+	require.NoError(err)
+
+	for _, line := range []string{"aaaa\n", "bbbb\n", "cccc\n"} {
+		_, err = outputs.Write([]byte(line))
+		require.NoError(err)
+	}
+
+	require.NoError(outputs.Close())
+
+	current, err := os.ReadFile(filename)
+	require.NoError(err)
+	require.Equal("cccc\n", string(current))
+
+	backup, err := os.ReadFile(filename + ".1")
+	require.NoError(err)
+	require.Equal("aaaa\nbbbb\n", string(backup))
+}
+
+func Test_Outputs_RotateBySize_ContinuesNumbering(t *testing.T) {
+	require := require.New(t)
+
+	filename := filepath.Join(t.TempDir(), "Test_Outputs_RotateBySize_ContinuesNumbering.log")
+	require.NoError(os.WriteFile(filename+".7", []byte("old\n"), 0o600))
+
+	outputs, err := outputs.New(filename, outputs.Opts{MaxSize: 1})
+	require.NoError(err)
+
+	for _, line := range []string{"a\n", "b\n"} {
+		_, err = outputs.Write([]byte(line))
+		require.NoError(err)
+	}
+
+	require.NoError(outputs.Close())
+
+	backup, err := os.ReadFile(filename + ".8")
+	require.NoError(err)
+	require.Equal("a\n", string(backup))
+}
+
+func Test_Outputs_RotateBySize_RenameFailure(t *testing.T) {
+	require := require.New(t)
+
+	filename := filepath.Join(t.TempDir(), "Test_Outputs_RotateBySize_RenameFailure.log")
+
+	outputs, err := outputs.New(filename, outputs.Opts{MaxSize: 1})
+	require.NoError(err)
+
+	for _, line := range []string{"a\n", "b\n"} {
+		_, err = outputs.Write([]byte(line))
+		require.NoError(err)
+	}
+
+	// A directory in place of the next backup fails the rename.
+	require.NoError(os.MkdirAll(filepath.Join(filename+".2", "dir"), 0o700))
+
+	n, err := outputs.Write([]byte("c\n"))
+	require.ErrorContains(err, "failed to rotate")
+	require.Equal(2, n)
+
+	require.NoError(os.RemoveAll(filename + ".2"))
+
+	_, err = outputs.Write([]byte("d\n"))
+	require.NoError(err)
+
+	require.NoError(outputs.Close())
+
+	// The record is written to the current file rather than dropped.
+	backup, err := os.ReadFile(filename + ".2")
+	require.NoError(err)
+	require.Equal("b\nc\n", string(backup))
+
+	current, err := os.ReadFile(filename)
+	require.NoError(err)
+	require.Equal("d\n", string(current))
+}
+
+func Test_Outputs_RotateBySize_OpenFailure(t *testing.T) {
+	tests := []struct {
+		name string
+		opts outputs.Opts
+		// backup is the suffix of the backup after Close, if it is kept.
+		backup string
+	}{
+		{"plain", outputs.Opts{MaxSize: 1}, ".1"},
+		// The backup being written is neither compressed nor removed.
+		{"compress", outputs.Opts{MaxSize: 1, Compress: true}, ".1.gz"},
+		{"max backups", outputs.Opts{MaxSize: 1, MaxBackups: 1}, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require := require.New(t)
+
+			filename := filepath.Join(t.TempDir(), "Test_Outputs_RotateBySize_OpenFailure.log")
+
+			// A backup newer than the ones to be created, which are
+			// removed first with MaxBackups.
+			future := time.Now().Add(time.Hour)
+			require.NoError(os.WriteFile(filename+".0", []byte("old\n"), 0o600))
+			require.NoError(os.Chtimes(filename+".0", future, future))
+
+			failures := 0
+
+			outputs.SetOpenFile(t, func(name string, flag int, perm fs.FileMode) (*os.File, error) {
+				if failures > 0 {
+					failures--
+
+					return nil, fs.ErrPermission
+				}
+
+				return os.OpenFile(name, flag, perm)
+			})
+
+			outputs, err := outputs.New(filename, tt.opts)
+			require.NoError(err)
+
+			_, err = outputs.Write([]byte("a\n"))
+			require.NoError(err)
+
+			failures = 3
+
+			// The file is renamed, but no new file can be opened, so
+			// the records go on to the backup and only the opening is
+			// retried.
+			for _, line := range []string{"b\n", "c\n", "d\n"} {
+				n, err := outputs.Write([]byte(line))
+				require.ErrorIs(err, fs.ErrPermission)
+				require.Equal(2, n)
+
+				// Let the cleaner run, if it has been triggered.
+				time.Sleep(50 * time.Millisecond)
+			}
+
+			backup, err := os.ReadFile(filename + ".1")
+			require.NoError(err)
+			require.Equal("a\nb\nc\nd\n", string(backup))
+
+			_, err = outputs.Write([]byte("e\n"))
+			require.NoError(err)
+
+			require.NoError(outputs.Close())
+
+			current, err := os.ReadFile(filename)
+			require.NoError(err)
+			require.Equal("e\n", string(current))
+
+			switch {
+			case strings.HasSuffix(tt.backup, ".gz"):
+				require.Equal("a\nb\nc\nd\n", readGzip(t, filename+tt.backup))
+			case tt.backup != "":
+				backup, err := os.ReadFile(filename + tt.backup)
+				require.NoError(err)
+				require.Equal("a\nb\nc\nd\n", string(backup))
+			}
+
+			_, err = os.Stat(filename + ".2")
+			require.ErrorIs(err, fs.ErrNotExist)
+		})
+	}
+}
+
+func Test_Outputs_RotateBySize_Concurrent(t *testing.T) {
+	require := require.New(t)
+
+	filename := filepath.Join(t.TempDir(), "Test_Outputs_RotateBySize_Concurrent.log")
+
+	outputs, err := outputs.New(filename, outputs.Opts{MaxSize: 1024})
+	require.NoError(err)
+
+	const (
+		writers = 8
+		lines   = 200
+	)
+
+	var wg sync.WaitGroup
+
+	for i := range writers {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for j := range lines {
+				_, err := fmt.Fprintf(outputs, "writer=%d line=%03d\n", i, j)
+				assert.NoError(t, err)
+			}
+		}()
+	}
+
+	wg.Wait()
+	require.NoError(outputs.Close())
+
+	got := readLines(t, filename+"*")
+	require.Len(got, writers*lines)
+
+	seen := make(map[string]struct{}, len(got))
+	for _, line := range got {
+		require.Regexp(`^writer=\d line=\d{3}$`, line)
+		seen[line] = struct{}{}
+	}
+
+	require.Len(seen, writers*lines)
+
+	backups, err := filepath.Glob(filename + ".*")
+	require.NoError(err)
+	require.NotEmpty(backups)
+
+	for _, backup := range backups {
+		info, err := os.Stat(backup)
+		require.NoError(err)
+		require.LessOrEqual(info.Size(), int64(1024))
+	}
+}
+
+func Test_Outputs_WriteAfterClose(t *testing.T) {
+	require := require.New(t)
+
+	filename := filepath.Join(t.TempDir(), "Test_Outputs_WriteAfterClose.log")
+
+	outputs, err := outputs.New(filename, outputs.Opts{MaxSize: 10})
+	require.NoError(err)
+	require.NoError(outputs.Close())
+
+	_, err = outputs.Write([]byte("log_message"))
+	require.ErrorIs(err, os.ErrClosed)
+}
+
+// fakeClock is a manually advanced clock for rotation tests.
+type fakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.now
+}
+
+func (c *fakeClock) Set(now time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.now = now
+}
+
+func Test_Outputs_RotateByTime_Pattern(t *testing.T) {
+	require := require.New(t)
+
+	dir := t.TempDir()
+	clock := &fakeClock{now: time.Date(2025, 1, 1, 23, 59, 59, 0, time.Local)}
+	symlink := filepath.Join(dir, "app.log")
+
+	outputs, err := outputs.New(filepath.Join(dir, "app-%Y-%m-%d.log"), outputs.Opts{
+		Interval: 24 * time.Hour,
+		Symlink:  symlink,
+		Now:      clock.Now,
+	})
+	require.NoError(err)
+
+	_, err = outputs.Write([]byte("first\n"))
+	require.NoError(err)
+
+	clock.Set(time.Date(2025, 1, 2, 0, 0, 1, 0, time.Local))
+
+	_, err = outputs.Write([]byte("second\n"))
+	require.NoError(err)
+	require.NoError(outputs.Close())
+
+	first, err := os.ReadFile(filepath.Join(dir, "app-2025-01-01.log"))
+	require.NoError(err)
+	require.Equal("first\n", string(first))
+
+	second, err := os.ReadFile(filepath.Join(dir, "app-2025-01-02.log"))
+	require.NoError(err)
+	require.Equal("second\n", string(second))
+
+	target, err := os.Readlink(symlink)
+	require.NoError(err)
+	require.Equal("app-2025-01-02.log", target)
+
+	viaLink, err := os.ReadFile(symlink)
+	require.NoError(err)
+	require.Equal("second\n", string(viaLink))
+}
+
+func Test_Outputs_RotateByTime_Hourly(t *testing.T) {
+	require := require.New(t)
+
+	dir := t.TempDir()
+	clock := &fakeClock{now: time.Date(2025, 1, 1, 10, 30, 0, 0, time.Local)}
+
+	outputs, err := outputs.New(filepath.Join(dir, "app-%Y%m%d%H.log"), outputs.Opts{
+		Interval: time.Hour,
+		Now:      clock.Now,
+	})
+	require.NoError(err)
+
+	for _, hour := range []int{10, 10, 11, 13} {
+		clock.Set(time.Date(2025, 1, 1, hour, 45, 0, 0, time.Local))
+
+		_, err = fmt.Fprintf(outputs, "hour=%d\n", hour)
+		require.NoError(err)
+	}
+
+	require.NoError(outputs.Close())
+
+	names, err := filepath.Glob(filepath.Join(dir, "*"))
+	require.NoError(err)
+	require.Equal([]string{
+		filepath.Join(dir, "app-2025010110.log"),
+		filepath.Join(dir, "app-2025010111.log"),
+		filepath.Join(dir, "app-2025010113.log"),
+	}, names)
+}
+
+func Test_Outputs_RotateByTime_NoPattern(t *testing.T) {
+	require := require.New(t)
+
+	filename := filepath.Join(t.TempDir(), "app.log")
+	clock := &fakeClock{now: time.Date(2025, 1, 1, 10, 0, 0, 0, time.Local)}
+
+	outputs, err := outputs.New(filename, outputs.Opts{
+		Interval: time.Hour,
+		Now:      clock.Now,
+	})
+	require.NoError(err)
+
+	_, err = outputs.Write([]byte("first\n"))
+	require.NoError(err)
+
+	clock.Set(time.Date(2025, 1, 1, 11, 0, 0, 0, time.Local))
+
+	_, err = outputs.Write([]byte("second\n"))
+	require.NoError(err)
+	require.NoError(outputs.Close())
+
+	backup, err := os.ReadFile(filename + ".1")
+	require.NoError(err)
+	require.Equal("first\n", string(backup))
+
+	current, err := os.ReadFile(filename)
+	require.NoError(err)
+	require.Equal("second\n", string(current))
+}
+
+func Test_New_BadRotation(t *testing.T) {
+	dir := t.TempDir()
+
+	testCases := []struct {
+		name  string
+		paths string
+		opts  outputs.Opts
+		err   string
+	}{
+		{
+			name:  "PatternWithoutInterval",
+			paths: filepath.Join(dir, "app-%Y.log"),
+			err:   "file name pattern requires rotation interval",
+		},
+		{
+			name:  "IntervalNotDividingDay",
+			paths: filepath.Join(dir, "app.log"),
+			opts:  outputs.Opts{Interval: 7 * time.Hour},
+			err:   "rotation interval 7h0m0s does not divide 24h",
+		},
+		{
+			name:  "SymlinkWithSeveralFiles",
+			paths: filepath.Join(dir, "a.log") + "," + filepath.Join(dir, "b.log"),
+			opts:  outputs.Opts{Symlink: filepath.Join(dir, "app.log")},
+			err:   "symlink requires a single file output",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := outputs.New(tc.paths, tc.opts)
+			require.ErrorContains(t, err, tc.err)
+		})
+	}
+}
+
+func readGzip(t *testing.T, name string) string {
+	t.Helper()
+
+	f, err := os.Open(name)
+	require.NoError(t, err)
+
+	defer func() { _ = f.Close() }()
+
+	zr, err := gzip.NewReader(f)
+	require.NoError(t, err)
+
+	data, err := io.ReadAll(zr)
+	require.NoError(t, err)
+
+	return string(data)
+}
+
+func Test_Outputs_Retention_MaxBackups(t *testing.T) {
+	require := require.New(t)
+
+	filename := filepath.Join(t.TempDir(), "app.log")
+
+	outputs, err := outputs.New(filename, outputs.Opts{MaxSize: 1, MaxBackups: 2})
+	require.NoError(err)
+
+	for _, line := range []string{"1\n", "2\n", "3\n", "4\n", "5\n"} {
+		_, err = outputs.Write([]byte(line))
+		require.NoError(err)
+	}
+
+	require.NoError(outputs.Close())
+
+	names, err := filepath.Glob(filename + "*")
+	require.NoError(err)
+	require.ElementsMatch([]string{filename, filename + ".3", filename + ".4"}, names)
+}
+
+func Test_Outputs_Retention_MaxAge(t *testing.T) {
+	require := require.New(t)
+
+	dir := t.TempDir()
+	now := time.Date(2025, 1, 10, 12, 0, 0, 0, time.Local)
+
+	for day := 1; day <= 9; day++ {
+		name := filepath.Join(dir, fmt.Sprintf("app-2025-01-%02d.log", day))
+		require.NoError(os.WriteFile(name, []byte("old\n"), 0o600))
+
+		modTime := time.Date(2025, 1, day, 23, 59, 0, 0, time.Local)
+		require.NoError(os.Chtimes(name, modTime, modTime))
+	}
+
+	outputs, err := outputs.New(filepath.Join(dir, "app-%Y-%m-%d.log"), outputs.Opts{
+		Interval: 24 * time.Hour,
+		MaxAge:   3 * 24 * time.Hour,
+		Now:      func() time.Time { return now },
+	})
+	require.NoError(err)
+	require.NoError(outputs.Close())
+
+	names, err := filepath.Glob(filepath.Join(dir, "*"))
+	require.NoError(err)
+	require.Equal([]string{
+		filepath.Join(dir, "app-2025-01-07.log"),
+		filepath.Join(dir, "app-2025-01-08.log"),
+		filepath.Join(dir, "app-2025-01-09.log"),
+		filepath.Join(dir, "app-2025-01-10.log"),
+	}, names)
+}
+
+func Test_Outputs_Retention_Compress(t *testing.T) {
+	require := require.New(t)
+
+	filename := filepath.Join(t.TempDir(), "app.log")
+
+	outputs, err := outputs.New(filename, outputs.Opts{MaxSize: 1, Compress: true})
+	require.NoError(err)
+
+	for _, line := range []string{"1\n", "2\n", "3\n"} {
+		_, err = outputs.Write([]byte(line))
+		require.NoError(err)
+	}
+
+	require.NoError(outputs.Close())
+
+	names, err := filepath.Glob(filename + "*")
+	require.NoError(err)
+	require.ElementsMatch([]string{filename, filename + ".1.gz", filename + ".2.gz"}, names)
+
+	require.Equal("1\n", readGzip(t, filename+".1.gz"))
+	require.Equal("2\n", readGzip(t, filename+".2.gz"))
+
+	current, err := os.ReadFile(filename)
+	require.NoError(err)
+	require.Equal("3\n", string(current))
+}
+
+func Test_Outputs_Retention_ErrorHandler(t *testing.T) {
+	require := require.New(t)
+
+	filename := filepath.Join(t.TempDir(), "app.log")
+	require.NoError(os.WriteFile(filename+".1", []byte("old\n"), 0o600))
+	// The compressor can not create its temporary file over a directory.
+	require.NoError(os.Mkdir(filename+".1.gz.tmp", 0o700))
+
+	var (
+		mu     sync.Mutex
+		failed []string
+	)
+
+	outputs, err := outputs.New(filename, outputs.Opts{
+		Compress: true,
+		ErrorHandler: func(path string, err error) {
+			mu.Lock()
+			defer mu.Unlock()
+
+			failed = append(failed, path)
+
+			assert.ErrorContains(t, err, "failed to compress backup")
+		},
+	})
+	require.NoError(err)
+	require.NoError(outputs.Close())
+
+	require.Equal([]string{filename + ".1"}, failed)
+}
+
+func Test_Outputs_Reopen(t *testing.T) {
+	require := require.New(t)
+
+	filename := filepath.Join(t.TempDir(), "app.log")
+
+	outputs, err := outputs.New(filename+",/dev/null", outputs.Opts{})
+	require.NoError(err)
+
+	_, err = outputs.Write([]byte("first\n"))
+	require.NoError(err)
+
+	// Emulate logrotate in "create" mode.
+	require.NoError(os.Rename(filename, filename+".old"))
+	require.NoError(outputs.Reopen())
+
+	_, err = outputs.Write([]byte("second\n"))
+	require.NoError(err)
+	require.NoError(outputs.Close())
+
+	old, err := os.ReadFile(filename + ".old")
+	require.NoError(err)
+	require.Equal("first\n", string(old))
+
+	current, err := os.ReadFile(filename)
+	require.NoError(err)
+	require.Equal("second\n", string(current))
+
+	require.ErrorIs(outputs.Reopen(), os.ErrClosed)
+}
+
+func Test_Outputs_Reopen_Concurrent(t *testing.T) {
+	require := require.New(t)
+
+	filename := filepath.Join(t.TempDir(), "app.log")
+
+	outputs, err := outputs.New(filename, outputs.Opts{})
+	require.NoError(err)
+
+	const (
+		writers = 4
+		lines   = 500
+		reopens = 20
+	)
+
+	var wg sync.WaitGroup
+
+	for i := range writers {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for j := range lines {
+				_, err := fmt.Fprintf(outputs, "writer=%d line=%03d\n", i, j)
+				assert.NoError(t, err)
+			}
+		}()
+	}
+
+	for i := range reopens {
+		assert.NoError(t, os.Rename(filename, fmt.Sprintf("%s.%d", filename, i)))
+		assert.NoError(t, outputs.Reopen())
+	}
+
+	wg.Wait()
+	require.NoError(outputs.Close())
+
+	require.Len(readLines(t, filename+"*"), writers*lines)
+}
+
+func Test_New_Modifiers(t *testing.T) {
+	require := require.New(t)
+
+	dir := t.TempDir()
+	isModifier := func(name string) bool {
+		return name == "error" || name == "json"
+	}
+
+	outputs, err := outputs.New("stderr:error,"+filepath.Join(dir, "a:b.log")+":json:error", outputs.Opts{
+		IsModifier: isModifier,
+	})
+	require.NoError(err)
+
+	defer func() {
+		_ = outputs.Close()
+	}()
+
+	list := outputs.List()
+	require.Len(list, 2)
+
+	require.Equal("stderr", list[0].Path)
+	require.Equal([]string{"error"}, list[0].Modifiers)
+
+	require.Equal(filepath.Join(dir, "a:b.log"), list[1].Path)
+	require.Equal([]string{"json", "error"}, list[1].Modifiers)
+
+	_, err = os.Stat(filepath.Join(dir, "a:b.log"))
+	require.NoError(err)
+}
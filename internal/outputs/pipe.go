@@ -0,0 +1,187 @@
+package outputs
+
+import (
+	"errors"
+	"io"
+	"net/url"
+	"os"
+	"os/exec"
+	"sync"
+	"time"
+)
+
+const pipePrefix = "pipe:"
+
+const (
+	// pipeRestartDelay is the minimum delay between starts of a command,
+	// so a command exiting right away does not make every record fork.
+	pipeRestartDelay = time.Second
+	// pipeCloseTimeout is how long Close waits for the command to exit
+	// after its stdin is closed before killing it.
+	pipeCloseTimeout = 5 * time.Second
+)
+
+var errPipeExited = errors.New("pipe command exited")
+
+// pipe writes records to stdin of a shell command, like Tarantool does
+// for "pipe:command". The command is restarted if it exits.
+type pipe struct {
+	command string
+	opts    Opts
+
+	mu    sync.Mutex
+	cmd   *exec.Cmd
+	stdin *os.File
+	// exited is closed once the current command exits.
+	exited  chan struct{}
+	started time.Time
+	closed  bool
+
+	// stream writes to stdin with Opts.NonBlock.
+	stream nonBlockStream
+}
+
+func newPipe(command string, opts Opts) (*pipe, error) {
+	if command == "" {
+		return nil, errors.New("pipe destination must be pipe:command")
+	}
+
+	p := &pipe{command: command, opts: opts}
+
+	if err := p.start(); err != nil {
+		return nil, err
+	}
+
+	return p, nil
+}
+
+// start starts the command. It inherits stdout and stderr of the process.
+func (p *pipe) start() error {
+	r, stdin, err := os.Pipe()
+	if err != nil {
+		return err
+	}
+
+	cmd := exec.Command("/bin/sh", "-c", p.command)
+	cmd.Stdin = r
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+
+	err = cmd.Start()
+	// The command has its own copy of the read end.
+	_ = r.Close()
+
+	if err != nil {
+		_ = stdin.Close()
+
+		return err
+	}
+
+	exited := make(chan struct{})
+
+	go func() {
+		_ = cmd.Wait()
+		close(exited)
+	}()
+
+	p.cmd, p.stdin, p.exited, p.started = cmd, stdin, exited, p.opts.now()
+	p.stream.torn = false
+
+	return nil
+}
+
+// restart starts the command again unless it was started less than
+// pipeRestartDelay ago.
+func (p *pipe) restart() error {
+	if p.opts.now().Sub(p.started) < pipeRestartDelay {
+		return errPipeExited
+	}
+
+	_ = p.stdin.Close()
+
+	return p.start()
+}
+
+func (p *pipe) hasExited() bool {
+	select {
+	case <-p.exited:
+		return true
+	default:
+		return false
+	}
+}
+
+// Write writes b to stdin of the command, restarting it first if it has
+// exited. If the write fails, the command is restarted and the write is
+// retried once.
+func (p *pipe) Write(b []byte) (int, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.closed {
+		return 0, os.ErrClosed
+	}
+
+	if p.hasExited() {
+		if err := p.restart(); err != nil {
+			return 0, err
+		}
+	}
+
+	if n, err := p.write(b); err == nil {
+		return n, nil
+	}
+
+	if err := p.restart(); err != nil {
+		return 0, err
+	}
+
+	return p.write(b)
+}
+
+// write writes b to stdin of the command. With Opts.NonBlock, b is
+// dropped if the command does not keep up with reading.
+func (p *pipe) write(b []byte) (int, error) {
+	if !p.opts.NonBlock {
+		return p.stdin.Write(b)
+	}
+
+	rc, err := p.stdin.SyscallConn()
+	if err != nil {
+		return 0, err
+	}
+
+	return p.stream.write(rc, b)
+}
+
+// Dropped returns the number of records which would block.
+func (p *pipe) Dropped() uint64 {
+	return p.stream.Dropped()
+}
+
+// Close closes stdin of the command and waits for it to exit. The command
+// is killed if it does not exit in time.
+func (p *pipe) Close() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.closed {
+		return nil
+	}
+
+	p.closed = true
+	err := p.stdin.Close()
+
+	select {
+	case <-p.exited:
+	case <-time.After(pipeCloseTimeout):
+		_ = p.cmd.Process.Kill()
+		<-p.exited
+	}
+
+	return err
+}
+
+func openPipeURL(u *url.URL, opts Opts) (io.WriteCloser, error) {
+	return newPipe(u.Opaque, opts)
+}
@@ -0,0 +1,127 @@
+package outputs_test
+
+import (
+	"log/slog"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/tarantool/go-tlog/internal/outputs"
+)
+
+func Test_Outputs_Pipe(t *testing.T) {
+	require := require.New(t)
+
+	path := filepath.Join(t.TempDir(), "app.log")
+
+	outputs, err := outputs.New("pipe:cat > "+path, outputs.Opts{})
+	require.NoError(err)
+
+	_, err = outputs.Write([]byte("first\n"))
+	require.NoError(err)
+	_, err = outputs.Write([]byte("second\n"))
+	require.NoError(err)
+
+	// Close waits for the command to exit.
+	require.NoError(outputs.Close())
+	require.Equal([]string{"first", "second"}, readLines(t, path))
+
+	_, err = outputs.Write([]byte("third\n"))
+	require.ErrorIs(err, os.ErrClosed)
+}
+
+func Test_Outputs_Pipe_Commas(t *testing.T) {
+	dir := t.TempDir()
+	t.Chdir(dir)
+
+	for _, command := range []string{"cut -d, -f1", "awk -F, '{print $1}'"} {
+		t.Run(command, func(t *testing.T) {
+			require := require.New(t)
+
+			path := filepath.Join(dir, "app.log")
+
+			outputs, err := outputs.New("stderr,pipe:"+command+" > "+path, outputs.Opts{})
+			require.NoError(err)
+
+			list := outputs.List()
+			require.Len(list, 2)
+			require.Equal("pipe:"+command+" > "+path, list[1].Path)
+
+			_, err = list[1].WriteLevel(slog.LevelInfo, []byte("first,second\n"))
+			require.NoError(err)
+
+			require.NoError(outputs.Close())
+			require.Equal([]string{"first"}, readLines(t, path))
+			require.NoError(os.Remove(path))
+		})
+	}
+
+	// No files are created from the parts of the commands.
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	require.Empty(t, entries)
+}
+
+func Test_Outputs_Pipe_Restart(t *testing.T) {
+	require := require.New(t)
+
+	path := filepath.Join(t.TempDir(), "app.log")
+	clock := &fakeClock{now: time.Date(2025, 1, 1, 0, 0, 0, 0, time.Local)}
+
+	// The command exits after every line.
+	outputs, err := outputs.New("pipe:head -n 1 >> "+path, outputs.Opts{Now: clock.Now})
+	require.NoError(err)
+
+	_, err = outputs.Write([]byte("first\n"))
+	require.NoError(err)
+
+	require.Eventually(func() bool {
+		return len(readLines(t, path)) == 1
+	}, 5*time.Second, 10*time.Millisecond)
+
+	// The command is not restarted too often.
+	require.Eventually(func() bool {
+		_, err := outputs.Write([]byte("lost\n"))
+
+		return err != nil
+	}, 5*time.Second, 10*time.Millisecond)
+
+	clock.Set(clock.Now().Add(time.Second))
+
+	_, err = outputs.Write([]byte("second\n"))
+	require.NoError(err)
+	require.NoError(outputs.Close())
+
+	require.Equal([]string{"first", "second"}, readLines(t, path))
+}
+
+func Test_New_BadPipe(t *testing.T) {
+	_, err := outputs.New("pipe:", outputs.Opts{})
+	require.EqualError(t, err, `failed to open path "pipe:": pipe destination must be pipe:command`)
+}
+
+func Test_Outputs_FilePrefix(t *testing.T) {
+	require := require.New(t)
+
+	path := filepath.Join(t.TempDir(), "app.log")
+
+	outputs, err := outputs.New("file:"+path, outputs.Opts{
+		MaxSize: 1,
+		Symlink: path + ".current",
+	})
+	require.NoError(err)
+
+	_, err = outputs.Write([]byte("message\n"))
+	require.NoError(err)
+	require.NoError(outputs.Close())
+
+	require.Equal([]string{"message"}, readLines(t, path))
+}
+
+func Test_New_BadFilePrefix(t *testing.T) {
+	_, err := outputs.New("file:", outputs.Opts{})
+	require.EqualError(t, err, `failed to open path "file:": file destination must be file:/path`)
+}
@@ -0,0 +1,156 @@
+package outputs
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/url"
+	"strconv"
+	"sync"
+	"sync/atomic"
+)
+
+const ringPrefix = "ring:"
+
+// ringEntry is a record kept by a ring.
+type ringEntry struct {
+	// seq is the sequence number of the record, starting from 1.
+	seq uint64
+	p   []byte
+}
+
+// ring is an in-memory output keeping the last records for post-mortem
+// dumps. Writes are lock-free: each one claims the next slot with
+// an atomic counter and publishes its record with an atomic store.
+type ring struct {
+	slots []atomic.Pointer[ringEntry]
+	// seq is the sequence number of the last claimed slot.
+	seq atomic.Uint64
+
+	// dumpMu serializes dumps of new records.
+	dumpMu sync.Mutex
+	// dumped is the sequence number of the last record dumped by DumpNew.
+	dumped uint64
+}
+
+// newRing creates a ring from the size given as "ring:N".
+func newRing(size string) (*ring, error) {
+	n, err := strconv.Atoi(size)
+	if err != nil || n <= 0 {
+		return nil, fmt.Errorf("ring size must be a positive number, got %q", size)
+	}
+
+	return &ring{slots: make([]atomic.Pointer[ringEntry], n)}, nil
+}
+
+func (r *ring) Write(p []byte) (int, error) {
+	seq := r.seq.Add(1)
+	r.slots[(seq-1)%uint64(len(r.slots))].Store(&ringEntry{seq: seq, p: bytes.Clone(p)})
+
+	return len(p), nil
+}
+
+// WriteLevel keeps p regardless of the level.
+func (r *ring) WriteLevel(_ slog.Level, p []byte) (int, error) {
+	return r.Write(p)
+}
+
+// dump writes the kept records with sequence numbers after the given one
+// to w, oldest first, and returns the sequence number of the last record
+// written. Records being overwritten concurrently are skipped.
+func (r *ring) dump(w io.Writer, after uint64) (uint64, error) {
+	last := r.seq.Load()
+	first := after + 1
+
+	if size := uint64(len(r.slots)); last > size && first <= last-size {
+		first = last - size + 1
+	}
+
+	for seq := first; seq <= last; seq++ {
+		e := r.slots[(seq-1)%uint64(len(r.slots))].Load()
+		if e == nil || e.seq != seq {
+			// Not published yet or already overwritten.
+			continue
+		}
+
+		if _, err := w.Write(e.p); err != nil {
+			return seq, err
+		}
+	}
+
+	return last, nil
+}
+
+// Dump writes all the kept records to w, oldest first.
+func (r *ring) Dump(w io.Writer) error {
+	_, err := r.dump(w, 0)
+
+	return err
+}
+
+// DumpNew writes the kept records not written by previous calls of
+// DumpNew to w, oldest first.
+func (r *ring) DumpNew(w io.Writer) error {
+	r.dumpMu.Lock()
+	defer r.dumpMu.Unlock()
+
+	last, err := r.dump(w, r.dumped)
+	r.dumped = last
+
+	return err
+}
+
+// Close does nothing, so the records can be dumped after Close.
+func (r *ring) Close() error {
+	return nil
+}
+
+func openRingURL(u *url.URL, _ Opts) (io.WriteCloser, error) {
+	return newRing(u.Opaque)
+}
+
+// ringer is an output keeping records for dumps.
+type ringer interface {
+	Dump(w io.Writer) error
+	DumpNew(w io.Writer) error
+}
+
+// HasRing reports whether any of the outputs is a "ring:N" output.
+func (o *Outputs) HasRing() bool {
+	for _, output := range o.outputs {
+		if _, ok := output.sink.(ringer); ok {
+			return true
+		}
+	}
+
+	return false
+}
+
+// DumpRing writes the records kept by "ring:N" outputs to w.
+func (o *Outputs) DumpRing(w io.Writer) error {
+	return o.dumpRing(func(r ringer) error {
+		return r.Dump(w)
+	})
+}
+
+// DumpRingNew writes the records kept by "ring:N" outputs since
+// the previous call of DumpRingNew to w.
+func (o *Outputs) DumpRingNew(w io.Writer) error {
+	return o.dumpRing(func(r ringer) error {
+		return r.DumpNew(w)
+	})
+}
+
+func (o *Outputs) dumpRing(dump func(r ringer) error) error {
+	errs := make([]error, 0, len(o.outputs))
+
+	for _, output := range o.outputs {
+		if r, ok := output.sink.(ringer); ok {
+			errs = append(errs, dump(r))
+		}
+	}
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,131 @@
+package outputs_test
+
+import (
+	"bytes"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/tarantool/go-tlog/internal/outputs"
+)
+
+func writeRecords(t *testing.T, outputs *outputs.Outputs, from, to int) {
+	t.Helper()
+
+	for i := from; i <= to; i++ {
+		_, err := outputs.Write([]byte(strconv.Itoa(i) + "\n"))
+		require.NoError(t, err)
+	}
+}
+
+func Test_Outputs_Ring(t *testing.T) {
+	require := require.New(t)
+
+	outputs, err := outputs.New("ring:3", outputs.Opts{QueueSize: 16})
+	require.NoError(err)
+
+	var buf bytes.Buffer
+
+	require.NoError(outputs.DumpRing(&buf))
+	require.Empty(buf.String())
+
+	writeRecords(t, outputs, 1, 2)
+	require.NoError(outputs.DumpRing(&buf))
+	require.Equal("1\n2\n", buf.String())
+
+	writeRecords(t, outputs, 3, 5)
+	require.NoError(outputs.Close())
+
+	// Records are kept after Close.
+	buf.Reset()
+	require.NoError(outputs.DumpRing(&buf))
+	require.Equal("3\n4\n5\n", buf.String())
+}
+
+func Test_Outputs_Ring_DumpNew(t *testing.T) {
+	require := require.New(t)
+
+	outputs, err := outputs.New("ring:3", outputs.Opts{})
+	require.NoError(err)
+
+	var buf bytes.Buffer
+
+	writeRecords(t, outputs, 1, 2)
+	require.NoError(outputs.DumpRingNew(&buf))
+	require.Equal("1\n2\n", buf.String())
+
+	buf.Reset()
+	writeRecords(t, outputs, 3, 3)
+	require.NoError(outputs.DumpRingNew(&buf))
+	require.Equal("3\n", buf.String())
+
+	buf.Reset()
+	require.NoError(outputs.DumpRingNew(&buf))
+	require.Empty(buf.String())
+
+	// Records overwritten since the previous dump are lost.
+	writeRecords(t, outputs, 4, 10)
+	require.NoError(outputs.DumpRingNew(&buf))
+	require.Equal("8\n9\n10\n", buf.String())
+
+	require.NoError(outputs.Close())
+}
+
+func Test_Outputs_Ring_Concurrent(t *testing.T) {
+	require := require.New(t)
+
+	const (
+		writers = 8
+		records = 1000
+		size    = 64
+	)
+
+	outputs, err := outputs.New("ring:"+strconv.Itoa(size), outputs.Opts{})
+	require.NoError(err)
+
+	var wg sync.WaitGroup
+
+	for w := range writers {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for i := range records {
+				_, _ = outputs.Write([]byte(strconv.Itoa(w*records+i) + "\n"))
+			}
+		}()
+	}
+
+	wg.Add(1)
+
+	go func() {
+		defer wg.Done()
+
+		for range 100 {
+			var buf bytes.Buffer
+
+			_ = outputs.DumpRing(&buf)
+		}
+	}()
+
+	wg.Wait()
+
+	var buf bytes.Buffer
+
+	require.NoError(outputs.DumpRing(&buf))
+	require.Len(strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n"), size)
+	require.NoError(outputs.Close())
+}
+
+func Test_New_BadRing(t *testing.T) {
+	for _, path := range []string{"ring:", "ring:0", "ring:-1", "ring:many"} {
+		t.Run(path, func(t *testing.T) {
+			_, err := outputs.New(path, outputs.Opts{})
+			require.ErrorContains(t, err, "ring size must be a positive number")
+		})
+	}
+}
@@ -0,0 +1,158 @@
+package outputs
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+)
+
+// Factory creates a sink for a destination given as a URL.
+type Factory func(u *url.URL) (io.WriteCloser, error)
+
+// factory creates a built-in sink, which may depend on Opts.
+type factory func(u *url.URL, opts Opts) (io.WriteCloser, error)
+
+var (
+	sinksMu sync.RWMutex
+	sinks   = map[string]factory{
+		"file":   openFileURL,
+		"pipe":   openPipeURL,
+		"ring":   openRingURL,
+		"syslog": openSyslogURL,
+		"tcp":    openNetURL,
+		"unix":   openNetURL,
+	}
+)
+
+// RegisterSink registers a factory of sinks for "scheme://..."
+// destinations. Schemes are case-insensitive. It fails if the scheme
+// is already registered.
+func RegisterSink(scheme string, f Factory) error {
+	if f == nil {
+		return errors.New("sink factory is nil")
+	}
+
+	if !isValidScheme(scheme) {
+		return fmt.Errorf("invalid scheme %q", scheme)
+	}
+
+	scheme = strings.ToLower(scheme)
+
+	sinksMu.Lock()
+	defer sinksMu.Unlock()
+
+	if _, ok := sinks[scheme]; ok {
+		return fmt.Errorf("sink for scheme %q is already registered", scheme)
+	}
+
+	sinks[scheme] = func(u *url.URL, _ Opts) (io.WriteCloser, error) {
+		return f(u)
+	}
+
+	return nil
+}
+
+// isValidScheme checks a scheme against RFC 3986 3.1.
+func isValidScheme(scheme string) bool {
+	if scheme == "" {
+		return false
+	}
+
+	for i, c := range scheme {
+		switch {
+		case 'a' <= c && c <= 'z', 'A' <= c && c <= 'Z':
+		case i > 0 && ('0' <= c && c <= '9' || c == '+' || c == '-' || c == '.'):
+		default:
+			return false
+		}
+	}
+
+	return true
+}
+
+const filePrefix = "file:"
+
+// parseURL resolves a destination to a URL. Destinations without
+// a scheme are "file" URLs. Tarantool-style "file:/path" is a "file" URL
+// too, "pipe:command", "syslog:..." and "ring:N" are opaque URLs, so
+// they may contain "://".
+func parseURL(path string) (*url.URL, error) {
+	switch {
+	case path == "":
+		return nil, errors.New("empty path")
+	case strings.HasPrefix(path, pipePrefix):
+		return &url.URL{Scheme: "pipe", Opaque: strings.TrimPrefix(path, pipePrefix)}, nil
+	case strings.HasPrefix(path, syslogPrefix):
+		return &url.URL{Scheme: "syslog", Opaque: strings.TrimPrefix(path, syslogPrefix)}, nil
+	case strings.HasPrefix(path, ringPrefix):
+		return &url.URL{Scheme: "ring", Opaque: strings.TrimPrefix(path, ringPrefix)}, nil
+	case strings.Contains(path, "://"):
+		return url.Parse(path)
+	case strings.HasPrefix(path, filePrefix):
+		name := strings.TrimPrefix(path, filePrefix)
+		if name == "" {
+			return nil, errors.New("file destination must be file:/path")
+		}
+
+		return &url.URL{Scheme: "file", Path: name}, nil
+	default:
+		return &url.URL{Scheme: "file", Path: path}, nil
+	}
+}
+
+// openSink creates a sink for u using the factory of its scheme.
+func openSink(u *url.URL, opts Opts) (io.WriteCloser, error) {
+	sinksMu.RLock()
+	f, ok := sinks[u.Scheme]
+	sinksMu.RUnlock()
+
+	if !ok {
+		return nil, fmt.Errorf("unknown scheme %q", u.Scheme)
+	}
+
+	return f(u, opts)
+}
+
+// isFile reports whether u is a file rather than a standard stream.
+func isFile(u *url.URL) bool {
+	return u.Scheme == "file" && u.Path != "stdout" && u.Path != "stderr"
+}
+
+func openFileURL(u *url.URL, opts Opts) (io.WriteCloser, error) {
+	if u.Host != "" && u.Host != "localhost" {
+		return nil, fmt.Errorf("file destination must be a path, got host %q", u.Host)
+	}
+
+	switch u.Path {
+	case "stdout":
+		// https://github.com/uber-go/zap/blob/6d482535bdd97f4d97b2f9573ac308f1cf9b574e/sink.go#L153-L154
+		return openStd(os.Stdout, opts)
+		// https://github.com/uber-go/zap/blob/6d482535bdd97f4d97b2f9573ac308f1cf9b574e/sink.go#L155-L156
+	case "stderr":
+		return openStd(os.Stderr, opts)
+	case "":
+		return nil, errors.New("empty path")
+	default:
+		return newFile(u.Path, opts)
+	}
+}
+
+func openStd(f *os.File, opts Opts) (io.WriteCloser, error) {
+	if opts.NonBlock {
+		return newStdNonBlock(f)
+	}
+
+	return f, nil
+}
+
+func openSyslogURL(u *url.URL, opts Opts) (io.WriteCloser, error) {
+	return newSyslog(u.Opaque, opts)
+}
+
+func openNetURL(u *url.URL, opts Opts) (io.WriteCloser, error) {
+	return newNet(u, opts)
+}
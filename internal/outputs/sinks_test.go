@@ -0,0 +1,134 @@
+package outputs_test
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/tarantool/go-tlog/internal/outputs"
+)
+
+// bufferSink is a sink collecting records in memory.
+type bufferSink struct {
+	mu     sync.Mutex
+	buf    bytes.Buffer
+	closed bool
+}
+
+func (s *bufferSink) Write(p []byte) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.buf.Write(p)
+}
+
+func (s *bufferSink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.closed = true
+
+	return nil
+}
+
+func Test_RegisterSink(t *testing.T) {
+	t.Parallel()
+
+	sink := &bufferSink{}
+
+	var got *url.URL
+
+	t.Cleanup(func() { outputs.UnregisterSink("outputs-test") })
+
+	err := outputs.RegisterSink("outputs-test", func(u *url.URL) (io.WriteCloser, error) {
+		got = u
+
+		return sink, nil
+	})
+	require.NoError(t, err)
+
+	outs, err := outputs.New("outputs-test://host/path?key=value", outputs.Opts{})
+	require.NoError(t, err)
+
+	require.Equal(t, "host", got.Host)
+	require.Equal(t, "/path", got.Path)
+	require.Equal(t, "value", got.Query().Get("key"))
+
+	_, err = outs.Write([]byte("hello\n"))
+	require.NoError(t, err)
+	require.NoError(t, outs.Close())
+
+	require.Equal(t, "hello\n", sink.buf.String())
+	require.True(t, sink.closed)
+}
+
+func Test_RegisterSink_Errors(t *testing.T) {
+	t.Parallel()
+
+	factory := func(*url.URL) (io.WriteCloser, error) {
+		return &bufferSink{}, nil
+	}
+
+	t.Cleanup(func() { outputs.UnregisterSink("outputs-test-dup") })
+	require.NoError(t, outputs.RegisterSink("outputs-test-dup", factory))
+
+	err := outputs.RegisterSink("outputs-test-dup", factory)
+	require.EqualError(t, err, `sink for scheme "outputs-test-dup" is already registered`)
+
+	err = outputs.RegisterSink("FILE", factory)
+	require.EqualError(t, err, `sink for scheme "file" is already registered`)
+
+	err = outputs.RegisterSink("1bad", factory)
+	require.EqualError(t, err, `invalid scheme "1bad"`)
+
+	err = outputs.RegisterSink("outputs-test-nil", nil)
+	require.EqualError(t, err, "sink factory is nil")
+}
+
+func Test_New_UnknownScheme(t *testing.T) {
+	t.Parallel()
+
+	_, err := outputs.New("stderr,outputs-test-unknown://x", outputs.Opts{})
+	require.EqualError(t, err,
+		`failed to open path "outputs-test-unknown://x": unknown scheme "outputs-test-unknown"`)
+}
+
+func Test_New_FactoryError(t *testing.T) {
+	t.Parallel()
+
+	t.Cleanup(func() { outputs.UnregisterSink("outputs-test-fail") })
+	require.NoError(t, outputs.RegisterSink("outputs-test-fail", func(*url.URL) (io.WriteCloser, error) {
+		return nil, errors.New("no way")
+	}))
+
+	_, err := outputs.New("outputs-test-fail://x", outputs.Opts{})
+	require.EqualError(t, err, `failed to open path "outputs-test-fail://x": no way`)
+}
+
+func Test_New_FileURL(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "app.log")
+
+	outs, err := outputs.New("file://"+path, outputs.Opts{})
+	require.NoError(t, err)
+
+	_, err = outs.Write([]byte("hello\n"))
+	require.NoError(t, err)
+	require.NoError(t, outs.Close())
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	require.Equal(t, "hello\n", string(data))
+
+	_, err = outputs.New("file://app.log", outputs.Opts{})
+	require.EqualError(t, err,
+		`failed to open path "file://app.log": file destination must be a path, got host "app.log"`)
+}
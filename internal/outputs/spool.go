@@ -0,0 +1,377 @@
+package outputs
+
+import (
+	"cmp"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+const (
+	// defaultSpoolMaxSize is the default cap of the spool size in bytes.
+	defaultSpoolMaxSize = 64 << 20
+	// spoolSegments is the number of segments of a full spool, so that
+	// the cap is enforced by removing a fraction of the records.
+	spoolSegments = 8
+
+	spoolExt        = ".seg"
+	spoolHeaderSize = 4
+)
+
+// spoolSegment is a segment file of a spool.
+type spoolSegment struct {
+	seq     uint64
+	size    int64
+	records int
+}
+
+// spoolPos is the position of a record in a spool.
+type spoolPos struct {
+	seq uint64
+	off int64
+}
+
+// spool is an on-disk queue of records. Records are appended to segment
+// files and read back in order, fully read segments are removed. Once
+// the spool reaches its cap, the oldest segment is removed to make room.
+// Each record is stored as a 4-byte big-endian length followed by
+// the record itself.
+//
+// Segments left by a previous run are picked up, so records survive
+// a restart. It is not safe for concurrent use.
+type spool struct {
+	dir         string
+	maxSize     int64
+	segmentSize int64
+
+	// segments are the segment files, the oldest first.
+	segments []spoolSegment
+	// size is the total size of the segments.
+	size int64
+	// w is the last segment opened for appending, nil if it is not open.
+	w *os.File
+	// r is the first segment opened for reading, nil if it is not open.
+	r *os.File
+	// pos is the position of the next record to read.
+	pos spoolPos
+	// read is the number of records read from the first segment.
+	read int
+	// next is the record at pos, if it has been read already.
+	next []byte
+	// dropped is the number of records removed before being read.
+	dropped uint64
+}
+
+// newSpool opens the spool in dir, creating dir if needed.
+func newSpool(dir string, maxSize int64) (*spool, error) {
+	if maxSize <= 0 {
+		maxSize = defaultSpoolMaxSize
+	}
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+
+	s := &spool{
+		dir:         dir,
+		maxSize:     maxSize,
+		segmentSize: max(maxSize/spoolSegments, 1),
+	}
+
+	if err := s.load(); err != nil {
+		s.close()
+
+		return nil, fmt.Errorf("failed to load spool %q: %w", dir, err)
+	}
+
+	return s, nil
+}
+
+func (s *spool) segmentPath(seq uint64) string {
+	return filepath.Join(s.dir, fmt.Sprintf("%020d%s", seq, spoolExt))
+}
+
+// load picks up segments left by a previous run.
+func (s *spool) load() error {
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		name, ok := strings.CutSuffix(entry.Name(), spoolExt)
+		if !ok || !entry.Type().IsRegular() {
+			continue
+		}
+
+		seq, err := strconv.ParseUint(name, 10, 64)
+		if err != nil {
+			continue
+		}
+
+		segment, err := s.scan(seq)
+		if err != nil {
+			return err
+		}
+
+		s.segments = append(s.segments, segment)
+		s.size += segment.size
+	}
+
+	slices.SortFunc(s.segments, func(a, b spoolSegment) int {
+		return cmp.Compare(a.seq, b.seq)
+	})
+
+	if len(s.segments) > 0 {
+		s.pos = spoolPos{seq: s.segments[0].seq}
+	}
+
+	return nil
+}
+
+// scan counts records of a segment and cuts off a partially written
+// record at its end, if any.
+func (s *spool) scan(seq uint64) (spoolSegment, error) {
+	segment := spoolSegment{seq: seq}
+
+	f, err := os.OpenFile(s.segmentPath(seq), os.O_RDWR, 0)
+	if err != nil {
+		return segment, err
+	}
+
+	defer func() {
+		_ = f.Close()
+	}()
+
+	info, err := f.Stat()
+	if err != nil {
+		return segment, err
+	}
+
+	var header [spoolHeaderSize]byte
+
+	for {
+		if _, err := f.ReadAt(header[:], segment.size); err != nil {
+			break
+		}
+
+		end := segment.size + spoolHeaderSize + int64(binary.BigEndian.Uint32(header[:]))
+		if end > info.Size() {
+			break
+		}
+
+		segment.size = end
+		segment.records++
+	}
+
+	if segment.size < info.Size() {
+		return segment, f.Truncate(segment.size)
+	}
+
+	return segment, nil
+}
+
+// empty reports whether all records have been read.
+func (s *spool) empty() bool {
+	switch len(s.segments) {
+	case 0:
+		return true
+	case 1:
+		return s.pos.off >= s.segments[0].size
+	default:
+		return false
+	}
+}
+
+// append appends a record. If the spool is full, the oldest segment is
+// removed first.
+func (s *spool) append(p []byte) error {
+	n := spoolHeaderSize + int64(len(p))
+
+	if n > s.maxSize {
+		s.dropped++
+
+		return nil
+	}
+
+	for len(s.segments) > 0 && s.size+n > s.maxSize {
+		s.dropFirst()
+	}
+
+	if last := len(s.segments) - 1; last < 0 || s.w == nil || s.segments[last].size+n > s.segmentSize {
+		if err := s.rotate(); err != nil {
+			return err
+		}
+	}
+
+	buf := make([]byte, spoolHeaderSize, n)
+	binary.BigEndian.PutUint32(buf, uint32(len(p)))
+	buf = append(buf, p...)
+
+	if _, err := s.w.Write(buf); err != nil {
+		return err
+	}
+
+	last := &s.segments[len(s.segments)-1]
+	last.size += n
+	last.records++
+	s.size += n
+
+	return nil
+}
+
+// rotate starts a new segment.
+func (s *spool) rotate() error {
+	seq := uint64(1)
+	if len(s.segments) > 0 {
+		seq = s.segments[len(s.segments)-1].seq + 1
+	}
+
+	f, err := os.OpenFile(s.segmentPath(seq), os.O_CREATE|os.O_EXCL|os.O_WRONLY|os.O_APPEND,
+		os.FileMode(defaultFilePerms))
+	if err != nil {
+		return err
+	}
+
+	if s.w != nil {
+		_ = s.w.Close()
+	}
+
+	s.w = f
+
+	if len(s.segments) == 0 {
+		s.pos = spoolPos{seq: seq}
+	}
+
+	s.segments = append(s.segments, spoolSegment{seq: seq})
+
+	return nil
+}
+
+// peek returns the next record and its position. Unreadable segments
+// are dropped.
+func (s *spool) peek() ([]byte, spoolPos, bool) {
+	for !s.empty() {
+		if s.next != nil {
+			return s.next, s.pos, true
+		}
+
+		first := s.segments[0]
+		if s.pos.off >= first.size {
+			s.removeFirst()
+
+			continue
+		}
+
+		p, err := s.readAt(first.seq, s.pos.off)
+		if err != nil {
+			s.dropFirst()
+
+			continue
+		}
+
+		s.next = p
+
+		return p, s.pos, true
+	}
+
+	return nil, spoolPos{}, false
+}
+
+func (s *spool) readAt(seq uint64, off int64) ([]byte, error) {
+	if s.r == nil {
+		r, err := os.Open(s.segmentPath(seq))
+		if err != nil {
+			return nil, err
+		}
+
+		s.r = r
+	}
+
+	var header [spoolHeaderSize]byte
+
+	if _, err := s.r.ReadAt(header[:], off); err != nil {
+		return nil, err
+	}
+
+	p := make([]byte, binary.BigEndian.Uint32(header[:]))
+
+	if _, err := s.r.ReadAt(p, off+spoolHeaderSize); err != nil {
+		if errors.Is(err, io.EOF) {
+			err = io.ErrUnexpectedEOF
+		}
+
+		return nil, err
+	}
+
+	return p, nil
+}
+
+// pop removes the record at pos, unless it has been dropped already.
+func (s *spool) pop(pos spoolPos) {
+	if s.next == nil || pos != s.pos {
+		return
+	}
+
+	s.pos.off += spoolHeaderSize + int64(len(s.next))
+	s.read++
+	s.next = nil
+
+	if s.pos.off >= s.segments[0].size {
+		s.removeFirst()
+	}
+}
+
+// removeFirst removes the first segment, which has been read.
+func (s *spool) removeFirst() {
+	first := s.segments[0]
+
+	if s.r != nil {
+		_ = s.r.Close()
+		s.r = nil
+	}
+
+	if len(s.segments) == 1 {
+		if s.w != nil {
+			_ = s.w.Close()
+			s.w = nil
+		}
+	}
+
+	_ = os.Remove(s.segmentPath(first.seq))
+
+	s.segments = s.segments[1:]
+	s.size -= first.size
+	s.read = 0
+	s.next = nil
+
+	if len(s.segments) > 0 {
+		s.pos = spoolPos{seq: s.segments[0].seq}
+	} else {
+		s.pos = spoolPos{}
+	}
+}
+
+// dropFirst removes the first segment, counting its unread records.
+func (s *spool) dropFirst() {
+	s.dropped += uint64(s.segments[0].records - s.read)
+	s.removeFirst()
+}
+
+func (s *spool) close() {
+	if s.r != nil {
+		_ = s.r.Close()
+		s.r = nil
+	}
+
+	if s.w != nil {
+		_ = s.w.Close()
+		s.w = nil
+	}
+}
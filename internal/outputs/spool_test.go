@@ -0,0 +1,197 @@
+package outputs_test
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/tarantool/go-tlog/internal/outputs"
+)
+
+// unusedAddr returns a TCP address with nobody listening on it.
+func unusedAddr(t *testing.T) string {
+	t.Helper()
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	addr := l.Addr().String()
+	require.NoError(t, l.Close())
+
+	return addr
+}
+
+func listen(t *testing.T, addr string) net.Listener {
+	t.Helper()
+
+	l, err := net.Listen("tcp", addr)
+	require.NoError(t, err)
+
+	t.Cleanup(func() {
+		_ = l.Close()
+	})
+
+	return l
+}
+
+func segments(t *testing.T, dir string) []string {
+	t.Helper()
+
+	names, err := filepath.Glob(filepath.Join(dir, "*", "*.seg"))
+	require.NoError(t, err)
+
+	return names
+}
+
+func numbers(from, to int) []string {
+	lines := make([]string, 0, to-from+1)
+	for i := from; i <= to; i++ {
+		lines = append(lines, fmt.Sprintf("record %03d", i))
+	}
+
+	return lines
+}
+
+func Test_Outputs_TCP_Spool(t *testing.T) {
+	require := require.New(t)
+
+	addr := unusedAddr(t)
+	dir := t.TempDir()
+
+	outputs, err := outputs.New("tcp://"+addr, outputs.Opts{
+		Backlog:    2,
+		MaxBackoff: 50 * time.Millisecond,
+		SpoolDir:   dir,
+	})
+	require.NoError(err)
+
+	defer func() {
+		_ = outputs.Close()
+	}()
+
+	lines := numbers(1, 100)
+	write(t, outputs, lines...)
+
+	require.NotEmpty(segments(t, dir))
+	require.Zero(outputs.Dropped())
+
+	// The collector comes back.
+	l := listen(t, addr)
+	require.Equal(lines, readNetLines(t, l, len(lines)))
+
+	// Replayed segments are removed.
+	require.Eventually(func() bool {
+		return len(segments(t, dir)) == 0
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+func Test_Outputs_TCP_Spool_Restart(t *testing.T) {
+	require := require.New(t)
+
+	addr := unusedAddr(t)
+	dir := t.TempDir()
+	opts := outputs.Opts{MaxBackoff: 50 * time.Millisecond, SpoolDir: dir}
+
+	first, err := outputs.New("tcp://"+addr, opts)
+	require.NoError(err)
+
+	write(t, first, numbers(1, 10)...)
+	require.NoError(first.Close())
+
+	// A crash in the middle of a record.
+	names := segments(t, dir)
+	require.Len(names, 1)
+
+	f, err := os.OpenFile(names[0], os.O_WRONLY|os.O_APPEND, 0)
+	require.NoError(err)
+	_, err = f.Write([]byte{0, 0, 1})
+	require.NoError(err)
+	require.NoError(f.Close())
+
+	l := listen(t, addr)
+
+	second, err := outputs.New("tcp://"+addr, opts)
+	require.NoError(err)
+
+	defer func() {
+		_ = second.Close()
+	}()
+
+	write(t, second, numbers(11, 12)...)
+	require.Equal(numbers(1, 12), readNetLines(t, l, 12))
+}
+
+func Test_Outputs_TCP_Spool_MaxSize(t *testing.T) {
+	require := require.New(t)
+
+	addr := unusedAddr(t)
+	dir := t.TempDir()
+
+	// Each record takes 4+11 bytes, a segment holds up to 8 records.
+	outputs, err := outputs.New("tcp://"+addr, outputs.Opts{
+		MaxBackoff:   50 * time.Millisecond,
+		SpoolDir:     dir,
+		SpoolMaxSize: 8 * 8 * 15,
+	})
+	require.NoError(err)
+
+	defer func() {
+		_ = outputs.Close()
+	}()
+
+	write(t, outputs, numbers(1, 100)...)
+
+	// Whole segments of the oldest records are dropped.
+	require.Equal(uint64(40), outputs.Dropped())
+
+	l := listen(t, addr)
+	require.Equal(numbers(41, 100), readNetLines(t, l, 60))
+}
+
+func Test_Outputs_TCP_Spool_RestartOrder(t *testing.T) {
+	require := require.New(t)
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(err)
+
+	addr := l.Addr().String()
+	dir := t.TempDir()
+	opts := outputs.Opts{MaxBackoff: 50 * time.Millisecond, SpoolDir: dir}
+
+	first, err := outputs.New("tcp://"+addr, opts)
+	require.NoError(err)
+
+	conn, err := l.Accept()
+	require.NoError(err)
+
+	// The collector goes away.
+	require.NoError(conn.Close())
+	require.NoError(l.Close())
+
+	// Let the output notice the closed connection.
+	time.Sleep(100 * time.Millisecond)
+
+	// The send of the first record fails, it is kept in the backlog,
+	// while the second one is spooled.
+	write(t, first, "record 001")
+	time.Sleep(50 * time.Millisecond)
+	write(t, first, "record 002")
+	require.NoError(first.Close())
+
+	l = listen(t, addr)
+
+	second, err := outputs.New("tcp://"+addr, opts)
+	require.NoError(err)
+
+	defer func() {
+		_ = second.Close()
+	}()
+
+	write(t, second, "record 003")
+	require.Equal(numbers(1, 3), readNetLines(t, l, 3))
+}
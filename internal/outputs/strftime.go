@@ -0,0 +1,80 @@
+package outputs
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// isPattern reports whether path contains strftime-style verbs.
+func isPattern(path string) bool {
+	return strings.ContainsRune(path, '%')
+}
+
+// strftime formats t according to a strftime-style pattern.
+// Supported verbs are %Y, %y, %m, %d, %j, %H, %M, %S and %%.
+// Unknown verbs are kept as is.
+func strftime(pattern string, t time.Time) string {
+	var b strings.Builder
+
+	for i := 0; i < len(pattern); i++ {
+		c := pattern[i]
+		if c != '%' || i+1 == len(pattern) {
+			b.WriteByte(c)
+
+			continue
+		}
+
+		i++
+
+		switch verb := pattern[i]; verb {
+		case 'Y':
+			b.WriteString(strconv.Itoa(t.Year()))
+		case 'y':
+			writePadded(&b, t.Year()%100, 2)
+		case 'm':
+			writePadded(&b, int(t.Month()), 2)
+		case 'd':
+			writePadded(&b, t.Day(), 2)
+		case 'j':
+			writePadded(&b, t.YearDay(), 3)
+		case 'H':
+			writePadded(&b, t.Hour(), 2)
+		case 'M':
+			writePadded(&b, t.Minute(), 2)
+		case 'S':
+			writePadded(&b, t.Second(), 2)
+		case '%':
+			b.WriteByte('%')
+		default:
+			b.WriteByte('%')
+			b.WriteByte(verb)
+		}
+	}
+
+	return b.String()
+}
+
+func writePadded(b *strings.Builder, n, width int) {
+	s := strconv.Itoa(n)
+	for i := len(s); i < width; i++ {
+		b.WriteByte('0')
+	}
+
+	b.WriteString(s)
+}
+
+// periodEnd returns the end of the rotation period containing t.
+// Periods are aligned to the local midnight, so interval must divide
+// 24 hours.
+func periodEnd(t time.Time, interval time.Duration) time.Time {
+	midnight := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+	end := midnight.Add((t.Sub(midnight)/interval + 1) * interval)
+
+	// Days are shorter or longer than 24 hours on DST transitions.
+	if next := midnight.AddDate(0, 0, 1); end.After(next) {
+		return next
+	}
+
+	return end
+}
@@ -0,0 +1,155 @@
+package outputs
+
+import (
+	"errors"
+	"io"
+	"log/slog"
+	"os"
+	"sync"
+	"time"
+)
+
+// SyncPolicy defines when outputs are committed to stable storage.
+type SyncPolicy int
+
+const (
+	// SyncNever leaves syncing to the operating system and Outputs.Sync.
+	SyncNever SyncPolicy = iota
+	// SyncLevel syncs an output after each record at or above
+	// Opts.SyncLevel.
+	SyncLevel
+	// SyncInterval syncs outputs every Opts.SyncInterval.
+	SyncInterval
+)
+
+// syncer is an output which can be committed to stable storage,
+// like a file.
+type syncer interface {
+	Sync() error
+}
+
+// flusher is an output buffering records, like an asynchronous one.
+type flusher interface {
+	Flush() error
+}
+
+// isStd reports whether w is stdout or stderr, which are never synced
+// or closed.
+func isStd(w io.Writer) bool {
+	return w == os.Stdout || w == os.Stderr
+}
+
+// levelSyncer syncs an output after each record at or above a level.
+type levelSyncer struct {
+	w     io.WriteCloser
+	level slog.Level
+}
+
+func (s *levelSyncer) Write(p []byte) (int, error) {
+	return s.WriteLevel(slog.LevelInfo, p)
+}
+
+func (s *levelSyncer) WriteLevel(level slog.Level, p []byte) (int, error) {
+	n, err := writeLevel(s.w, level, p)
+	if err != nil || level < s.level {
+		return n, err
+	}
+
+	return n, s.w.(syncer).Sync()
+}
+
+// Sync syncs the underlying output.
+func (s *levelSyncer) Sync() error {
+	return s.w.(syncer).Sync()
+}
+
+// Reopen reopens the underlying output, if it supports reopening.
+func (s *levelSyncer) Reopen() error {
+	if r, ok := s.w.(reopener); ok {
+		return r.Reopen()
+	}
+
+	return nil
+}
+
+func (s *levelSyncer) Close() error {
+	return s.w.Close()
+}
+
+// sync syncs the output, it is a no-op for outputs without syncing.
+// Failures are reported as write failures.
+func (o *Output) sync() error {
+	s, ok := o.sink.(syncer)
+	if !ok || isStd(o.sink) {
+		return nil
+	}
+
+	err := s.Sync()
+	if err != nil {
+		o.reporter.fail(err)
+	}
+
+	return err
+}
+
+// Sync writes out records queued by asynchronous outputs and commits
+// file outputs to stable storage. It fails with os.ErrClosed once
+// Close has been called.
+func (o *Outputs) Sync() error {
+	if o.closed.Load() {
+		return os.ErrClosed
+	}
+
+	return o.sync()
+}
+
+func (o *Outputs) sync() error {
+	errs := make([]error, 0, len(o.outputs))
+
+	for _, output := range o.outputs {
+		if f, ok := output.w.(flusher); ok {
+			if err := f.Flush(); err != nil {
+				errs = append(errs, err)
+
+				continue
+			}
+		}
+
+		errs = append(errs, output.sync())
+	}
+
+	return errors.Join(errs...)
+}
+
+// syncEvery syncs the outputs every interval from a background goroutine.
+// Queued records are not waited for. The returned function stops it.
+func (o *Outputs) syncEvery(interval time.Duration) (stop func()) {
+	ticker := time.NewTicker(interval)
+	done := make(chan struct{})
+
+	var wg sync.WaitGroup
+
+	wg.Add(1)
+
+	go func() {
+		defer wg.Done()
+
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				for _, output := range o.outputs {
+					// Failures are reported by sync.
+					_ = output.sync()
+				}
+			}
+		}
+	}()
+
+	return func() {
+		ticker.Stop()
+		close(done)
+		wg.Wait()
+	}
+}
@@ -0,0 +1,213 @@
+package outputs_test
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/tarantool/go-tlog/internal/outputs"
+)
+
+// fakeFile is a sink recording writes and syncs.
+type fakeFile struct {
+	mu     sync.Mutex
+	events []string
+}
+
+func (f *fakeFile) Write(p []byte) (int, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.events = append(f.events, "write "+string(p))
+
+	return len(p), nil
+}
+
+func (f *fakeFile) Sync() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.events = append(f.events, "sync")
+
+	return nil
+}
+
+func (f *fakeFile) Close() error {
+	return nil
+}
+
+func (f *fakeFile) Events() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return append([]string(nil), f.events...)
+}
+
+var (
+	fakeFilesMu sync.Mutex
+	fakeFiles   = map[string]*fakeFile{}
+	fakeFilesN  atomic.Int64
+)
+
+func init() {
+	err := outputs.RegisterSink("fakefile", func(u *url.URL) (io.WriteCloser, error) {
+		fakeFilesMu.Lock()
+		defer fakeFilesMu.Unlock()
+
+		return fakeFiles[u.Host], nil
+	})
+	if err != nil {
+		panic(err)
+	}
+}
+
+// newFakeFile returns a fake file and its "fakefile://" path.
+func newFakeFile() (*fakeFile, string) {
+	fakeFilesMu.Lock()
+	defer fakeFilesMu.Unlock()
+
+	name := fmt.Sprintf("f%d", fakeFilesN.Add(1))
+	fakeFiles[name] = &fakeFile{}
+
+	return fakeFiles[name], "fakefile://" + name
+}
+
+func Test_Outputs_Sync_Never(t *testing.T) {
+	require := require.New(t)
+
+	f, path := newFakeFile()
+
+	outputs, err := outputs.New(path, outputs.Opts{})
+	require.NoError(err)
+
+	_, err = outputs.List()[0].WriteLevel(slog.LevelError, []byte("a"))
+	require.NoError(err)
+	require.Equal([]string{"write a"}, f.Events())
+
+	require.NoError(outputs.Sync())
+	require.Equal([]string{"write a", "sync"}, f.Events())
+
+	_, err = outputs.Write([]byte("b"))
+	require.NoError(err)
+
+	require.NoError(outputs.Close())
+	require.Equal([]string{"write a", "sync", "write b", "sync"}, f.Events())
+
+	require.ErrorIs(outputs.Sync(), os.ErrClosed)
+	require.NoError(outputs.Close())
+}
+
+func Test_Outputs_Sync_Level(t *testing.T) {
+	require := require.New(t)
+
+	f, path := newFakeFile()
+
+	outputs, err := outputs.New(path, outputs.Opts{
+		Sync:      outputs.SyncLevel,
+		SyncLevel: slog.LevelWarn,
+	})
+	require.NoError(err)
+
+	output := outputs.List()[0]
+
+	for _, level := range []slog.Level{slog.LevelInfo, slog.LevelWarn, slog.LevelDebug, slog.LevelError} {
+		_, err = output.WriteLevel(level, []byte(level.String()))
+		require.NoError(err)
+	}
+
+	require.Equal([]string{
+		"write INFO",
+		"write WARN", "sync",
+		"write DEBUG",
+		"write ERROR", "sync",
+	}, f.Events())
+
+	require.NoError(outputs.Close())
+}
+
+func Test_Outputs_Sync_Interval(t *testing.T) {
+	require := require.New(t)
+
+	f, path := newFakeFile()
+
+	outputs, err := outputs.New(path, outputs.Opts{
+		Sync:         outputs.SyncInterval,
+		SyncInterval: 10 * time.Millisecond,
+	})
+	require.NoError(err)
+
+	_, err = outputs.Write([]byte("a"))
+	require.NoError(err)
+
+	require.Eventually(func() bool {
+		return len(f.Events()) >= 3
+	}, 5*time.Second, 10*time.Millisecond)
+
+	require.NoError(outputs.Close())
+
+	events := f.Events()
+	require.Equal([]string{"write a", "sync", "sync"}, events[:3])
+
+	// Syncing stops with Close.
+	time.Sleep(50 * time.Millisecond)
+	require.Equal(events, f.Events())
+}
+
+func Test_Outputs_Sync_Async(t *testing.T) {
+	require := require.New(t)
+
+	f, path := newFakeFile()
+
+	outputs, err := outputs.New(path, outputs.Opts{
+		QueueSize: 16,
+		Overflow:  outputs.OverflowDropOldest,
+	})
+	require.NoError(err)
+
+	for _, p := range []string{"a", "b", "c"} {
+		_, err = outputs.Write([]byte(p))
+		require.NoError(err)
+	}
+
+	require.NoError(outputs.Sync())
+	require.Equal([]string{"write a", "write b", "write c", "sync"}, f.Events())
+
+	require.NoError(outputs.Close())
+}
+
+func Test_New_BadSyncInterval(t *testing.T) {
+	require := require.New(t)
+
+	_, path := newFakeFile()
+
+	_, err := outputs.New(path, outputs.Opts{Sync: outputs.SyncInterval})
+	require.EqualError(err, "sync interval must be positive, got 0s")
+}
+
+func Test_Outputs_Sync_File(t *testing.T) {
+	require := require.New(t)
+
+	path := filepath.Join(t.TempDir(), "app.log")
+
+	outputs, err := outputs.New("stderr,/dev/null,"+path, outputs.Opts{
+		Sync:      outputs.SyncLevel,
+		SyncLevel: slog.LevelInfo,
+	})
+	require.NoError(err)
+
+	_, err = outputs.Write([]byte("message\n"))
+	require.NoError(err)
+	require.NoError(outputs.Sync())
+	require.NoError(outputs.Close())
+
+	require.Equal([]string{"message"}, readLines(t, path))
+}
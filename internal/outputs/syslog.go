@@ -0,0 +1,336 @@
+package outputs
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const syslogPrefix = "syslog:"
+
+// defaultSyslogSocket is the local syslog socket used without server.
+var defaultSyslogSocket = "/dev/log"
+
+// syslogFacilities maps facility names to their codes, RFC 5424 6.2.1.
+var syslogFacilities = map[string]int{
+	"kern":     0,
+	"user":     1,
+	"mail":     2,
+	"daemon":   3,
+	"auth":     4,
+	"syslog":   5,
+	"lpr":      6,
+	"news":     7,
+	"uucp":     8,
+	"cron":     9,
+	"authpriv": 10,
+	"ftp":      11,
+	"local0":   16,
+	"local1":   17,
+	"local2":   18,
+	"local3":   19,
+	"local4":   20,
+	"local5":   21,
+	"local6":   22,
+	"local7":   23,
+}
+
+// Syslog severities, RFC 5424 6.2.1.
+const (
+	severityCritical = 2
+	severityError    = 3
+	severityWarning  = 4
+	severityInfo     = 6
+	severityDebug    = 7
+)
+
+// syslogSeverity maps a record level to a syslog severity.
+func syslogSeverity(level slog.Level) int {
+	switch {
+	// Levels above error, like fatal, have no counterparts in slog.
+	case level > slog.LevelError:
+		return severityCritical
+	case level >= slog.LevelError:
+		return severityError
+	case level >= slog.LevelWarn:
+		return severityWarning
+	case level >= slog.LevelInfo:
+		return severityInfo
+	default:
+		return severityDebug
+	}
+}
+
+type syslogProtocol int
+
+const (
+	rfc3164 syslogProtocol = iota
+	rfc5424
+)
+
+// syslogConfig is a parsed "syslog:key=value,..." destination.
+type syslogConfig struct {
+	// network and address of the syslog server.
+	network  string
+	address  string
+	identity string
+	facility int
+	protocol syslogProtocol
+}
+
+// isSyslogOption reports whether s looks like a "key=value" option
+// of a syslog destination.
+func isSyslogOption(s string) bool {
+	key, _, ok := strings.Cut(s, "=")
+	if !ok || key == "" {
+		return false
+	}
+
+	for _, c := range key {
+		if c < 'a' || c > 'z' {
+			return false
+		}
+	}
+
+	return true
+}
+
+// parseSyslog parses options of a syslog destination. Supported keys are:
+//   - server: "host:port" for UDP or a unix datagram socket path,
+//     optionally prefixed with "unix:" as in Tarantool, default is
+//     /dev/log;
+//   - identity: application name, default is the program name;
+//   - facility: facility name, default is "local7";
+//   - protocol: "rfc3164" (default) or "rfc5424".
+func parseSyslog(options string) (syslogConfig, error) {
+	cfg := syslogConfig{
+		network:  "unixgram",
+		address:  defaultSyslogSocket,
+		identity: filepath.Base(os.Args[0]),
+		facility: syslogFacilities["local7"],
+	}
+
+	if options == "" {
+		return cfg, nil
+	}
+
+	for _, option := range strings.Split(options, ",") {
+		key, value, ok := strings.Cut(option, "=")
+		if !ok || value == "" {
+			return cfg, fmt.Errorf("syslog option %q must be key=value", option)
+		}
+
+		switch key {
+		case "server":
+			switch socket, isUnix := strings.CutPrefix(value, "unix:"); {
+			case isUnix && socket == "":
+				return cfg, errors.New("syslog server must be unix:/path or host:port")
+			case isUnix:
+				cfg.network, cfg.address = "unixgram", socket
+			case strings.HasPrefix(value, "/"):
+				cfg.network, cfg.address = "unixgram", value
+			default:
+				cfg.network, cfg.address = "udp", value
+			}
+		case "identity":
+			cfg.identity = value
+		case "facility":
+			facility, ok := syslogFacilities[value]
+			if !ok {
+				return cfg, fmt.Errorf("unknown syslog facility %q", value)
+			}
+
+			cfg.facility = facility
+		case "protocol":
+			switch value {
+			case "rfc3164":
+				cfg.protocol = rfc3164
+			case "rfc5424":
+				cfg.protocol = rfc5424
+			default:
+				return cfg, fmt.Errorf("unknown syslog protocol %q", value)
+			}
+		default:
+			return cfg, fmt.Errorf("unknown syslog option %q", key)
+		}
+	}
+
+	return cfg, nil
+}
+
+// syslogWriter writes records as syslog messages, one datagram each.
+type syslogWriter struct {
+	cfg      syslogConfig
+	opts     Opts
+	hostname string
+	pid      int
+
+	mu   sync.Mutex
+	conn net.Conn
+
+	// dropped is the number of messages which would block with
+	// Opts.NonBlock.
+	dropped atomic.Uint64
+}
+
+// newSyslog creates a syslog output from comma-separated options,
+// see parseSyslog.
+func newSyslog(options string, opts Opts) (*syslogWriter, error) {
+	cfg, err := parseSyslog(options)
+	if err != nil {
+		return nil, err
+	}
+
+	hostname, _ := os.Hostname()
+	if hostname == "" {
+		hostname = "-"
+	}
+
+	w := &syslogWriter{
+		cfg:      cfg,
+		opts:     opts,
+		hostname: hostname,
+		pid:      os.Getpid(),
+	}
+
+	if w.conn, err = net.Dial(cfg.network, cfg.address); err != nil {
+		return nil, err
+	}
+
+	return w, nil
+}
+
+// Write writes p as a message of the informational severity.
+func (w *syslogWriter) Write(p []byte) (int, error) {
+	return w.WriteLevel(slog.LevelInfo, p)
+}
+
+// WriteLevel writes p as a message with the severity of level.
+// The connection is redialed once if the write fails, e.g. after
+// the syslog daemon has been restarted.
+func (w *syslogWriter) WriteLevel(level slog.Level, p []byte) (int, error) {
+	msg := w.format(level, p)
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.conn == nil {
+		return 0, os.ErrClosed
+	}
+
+	if err := w.write(msg); err == nil {
+		return len(p), nil
+	}
+
+	conn, err := net.Dial(w.cfg.network, w.cfg.address)
+	if err != nil {
+		return 0, err
+	}
+
+	_ = w.conn.Close()
+	w.conn = conn
+
+	if err := w.write(msg); err != nil {
+		return 0, err
+	}
+
+	return len(p), nil
+}
+
+// write sends msg. With Opts.NonBlock, msg is dropped if the socket
+// buffer is full, e.g. when the syslog daemon is stuck.
+func (w *syslogWriter) write(msg []byte) error {
+	if !w.opts.NonBlock {
+		_, err := w.conn.Write(msg)
+
+		return err
+	}
+
+	rc, err := rawConn(w.conn)
+	if err != nil {
+		return err
+	}
+
+	_, err = writeNonBlock(rc, msg)
+	if errors.Is(err, errWouldBlock) {
+		w.dropped.Add(1)
+
+		return nil
+	}
+
+	return err
+}
+
+// Dropped returns the number of messages which would block.
+func (w *syslogWriter) Dropped() uint64 {
+	return w.dropped.Load()
+}
+
+// format adds a syslog header to a record.
+func (w *syslogWriter) format(level slog.Level, p []byte) []byte {
+	var b bytes.Buffer
+
+	now := w.opts.now()
+	pri := w.cfg.facility*8 + syslogSeverity(level)
+
+	b.WriteByte('<')
+	b.WriteString(strconv.Itoa(pri))
+	b.WriteByte('>')
+
+	switch w.cfg.protocol {
+	case rfc5424:
+		// <PRI>1 TIMESTAMP HOSTNAME APP-NAME PROCID MSGID SD MSG
+		b.WriteString("1 ")
+		b.WriteString(now.Format(time.RFC3339Nano))
+		b.WriteByte(' ')
+		b.WriteString(w.hostname)
+		b.WriteByte(' ')
+		b.WriteString(w.cfg.identity)
+		b.WriteByte(' ')
+		b.WriteString(strconv.Itoa(w.pid))
+		b.WriteString(" - - ")
+	default:
+		// <PRI>TIMESTAMP [HOSTNAME ]TAG[PID]: MSG, the local syslog
+		// daemon adds the hostname on its own.
+		b.WriteString(now.Format(time.Stamp))
+		b.WriteByte(' ')
+
+		if w.cfg.network != "unixgram" {
+			b.WriteString(w.hostname)
+			b.WriteByte(' ')
+		}
+
+		b.WriteString(w.cfg.identity)
+		b.WriteByte('[')
+		b.WriteString(strconv.Itoa(w.pid))
+		b.WriteString("]: ")
+	}
+
+	b.Write(bytes.TrimSuffix(p, []byte("\n")))
+
+	return b.Bytes()
+}
+
+// Close closes the connection to the syslog server.
+func (w *syslogWriter) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.conn == nil {
+		return nil
+	}
+
+	err := w.conn.Close()
+	w.conn = nil
+
+	return err
+}
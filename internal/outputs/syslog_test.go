@@ -0,0 +1,241 @@
+package outputs_test
+
+import (
+	"fmt"
+	"log/slog"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/tarantool/go-tlog/internal/outputs"
+)
+
+// listenSyslog starts a unix datagram socket standing in for /dev/log.
+func listenSyslog(t *testing.T) (string, *net.UnixConn) {
+	t.Helper()
+
+	// Socket paths are limited to ~100 bytes, t.TempDir may be longer.
+	dir, err := os.MkdirTemp("", "tlog")
+	require.NoError(t, err)
+
+	t.Cleanup(func() {
+		_ = os.RemoveAll(dir)
+	})
+
+	path := filepath.Join(dir, "log.sock")
+
+	conn, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Name: path, Net: "unixgram"})
+	require.NoError(t, err)
+
+	t.Cleanup(func() {
+		_ = conn.Close()
+	})
+
+	return path, conn
+}
+
+func readDatagram(t *testing.T, conn net.PacketConn) string {
+	t.Helper()
+
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(5*time.Second)))
+
+	buf := make([]byte, 64<<10)
+
+	n, _, err := conn.ReadFrom(buf)
+	require.NoError(t, err)
+
+	return string(buf[:n])
+}
+
+func Test_Outputs_Syslog_RFC3164(t *testing.T) {
+	require := require.New(t)
+
+	socket, conn := listenSyslog(t)
+	now := time.Date(2025, 3, 7, 10, 4, 5, 0, time.Local)
+
+	outputs, err := outputs.New("stdout:info,syslog:server="+socket+",identity=myapp,facility=local0:error",
+		outputs.Opts{
+			IsModifier: func(name string) bool { return name == "info" || name == "error" },
+			Now:        func() time.Time { return now },
+		})
+	require.NoError(err)
+
+	defer func() {
+		_ = outputs.Close()
+	}()
+
+	list := outputs.List()
+	require.Len(list, 2)
+	require.Equal([]string{"error"}, list[1].Modifiers)
+
+	_, err = list[1].WriteLevel(slog.LevelError, []byte("my error message\n"))
+	require.NoError(err)
+
+	// local0 * 8 + error.
+	require.Equal(fmt.Sprintf("<131>Mar  7 10:04:05 myapp[%d]: my error message", os.Getpid()),
+		readDatagram(t, conn))
+
+	_, err = list[1].WriteLevel(slog.LevelDebug-4, []byte("my trace message\n"))
+	require.NoError(err)
+
+	// local0 * 8 + debug.
+	require.Contains(readDatagram(t, conn), "<135>")
+
+	_, err = list[1].WriteLevel(slog.LevelError+8, []byte("my fatal message\n"))
+	require.NoError(err)
+
+	// local0 * 8 + critical.
+	require.Contains(readDatagram(t, conn), "<130>")
+}
+
+func Test_Outputs_Syslog_Modifiers(t *testing.T) {
+	socket, conn := listenSyslog(t)
+	outputs.SetDefaultSyslogSocket(t, socket)
+
+	// A file named "syslog" must not be created instead.
+	dir := t.TempDir()
+	t.Chdir(dir)
+
+	tests := []struct {
+		path      string
+		modifiers []string
+	}{
+		{"syslog:error", []string{"error"}},
+		{"syslog:warn:json", []string{"warn", "json"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			require := require.New(t)
+
+			outputs, err := outputs.New(tt.path, outputs.Opts{
+				IsModifier: func(name string) bool {
+					return name == "error" || name == "warn" || name == "json"
+				},
+			})
+			require.NoError(err)
+
+			defer func() {
+				require.NoError(outputs.Close())
+			}()
+
+			list := outputs.List()
+			require.Len(list, 1)
+			require.Equal("syslog:", list[0].Path)
+			require.Equal(tt.modifiers, list[0].Modifiers)
+
+			_, err = list[0].WriteLevel(slog.LevelError, []byte("message\n"))
+			require.NoError(err)
+
+			require.Regexp(`^<187>.*\]: message$`, readDatagram(t, conn))
+		})
+	}
+
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	require.Empty(t, entries)
+}
+
+func Test_Outputs_Syslog_TarantoolServer(t *testing.T) {
+	require := require.New(t)
+
+	socket, conn := listenSyslog(t)
+
+	outputs, err := outputs.New("syslog:server=unix:"+socket+",identity=tarantool,facility=user", outputs.Opts{})
+	require.NoError(err)
+
+	_, err = outputs.Write([]byte("message\n"))
+	require.NoError(err)
+
+	// user * 8 + info.
+	require.Regexp(`^<14>.* tarantool\[\d+\]: message$`, readDatagram(t, conn))
+	require.NoError(outputs.Close())
+}
+
+func Test_Outputs_Syslog_RFC5424_UDP(t *testing.T) {
+	require := require.New(t)
+
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(err)
+
+	defer func() {
+		_ = conn.Close()
+	}()
+
+	now := time.Date(2025, 3, 7, 10, 4, 5, 0, time.UTC)
+
+	outputs, err := outputs.New("syslog:server="+conn.LocalAddr().String()+",identity=myapp,protocol=rfc5424",
+		outputs.Opts{Now: func() time.Time { return now }})
+	require.NoError(err)
+
+	defer func() {
+		_ = outputs.Close()
+	}()
+
+	_, err = outputs.List()[0].WriteLevel(slog.LevelWarn, []byte("my warn message\n"))
+	require.NoError(err)
+
+	hostname, err := os.Hostname()
+	require.NoError(err)
+
+	// local7 * 8 + warning.
+	require.Equal(fmt.Sprintf("<188>1 2025-03-07T10:04:05Z %s myapp %d - - my warn message", hostname, os.Getpid()),
+		readDatagram(t, conn))
+}
+
+func Test_Outputs_Syslog_Reconnect(t *testing.T) {
+	require := require.New(t)
+
+	socket, conn := listenSyslog(t)
+
+	outputs, err := outputs.New("syslog:server="+socket, outputs.Opts{})
+	require.NoError(err)
+
+	defer func() {
+		_ = outputs.Close()
+	}()
+
+	_, err = outputs.Write([]byte("first\n"))
+	require.NoError(err)
+	require.Contains(readDatagram(t, conn), "first")
+
+	// Emulate a restart of the syslog daemon.
+	require.NoError(conn.Close())
+	require.NoError(os.Remove(socket))
+
+	conn, err = net.ListenUnixgram("unixgram", &net.UnixAddr{Name: socket, Net: "unixgram"})
+	require.NoError(err)
+
+	defer func() {
+		_ = conn.Close()
+	}()
+
+	_, err = outputs.Write([]byte("second\n"))
+	require.NoError(err)
+	require.Contains(readDatagram(t, conn), "second")
+}
+
+func Test_New_BadSyslog(t *testing.T) {
+	testCases := []struct {
+		path string
+		err  string
+	}{
+		{"syslog:facility=local9", `unknown syslog facility "local9"`},
+		{"syslog:protocol=rfc1", `unknown syslog protocol "rfc1"`},
+		{"syslog:color=red", `unknown syslog option "color"`},
+		{"syslog:identity=", `syslog option "identity=" must be key=value`},
+		{"syslog:server=/not/exist", "dial unixgram /not/exist"},
+		{"syslog:server=unix:", "syslog server must be unix:/path or host:port"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.path, func(t *testing.T) {
+			_, err := outputs.New(tc.path, outputs.Opts{})
+			require.ErrorContains(t, err, tc.err)
+		})
+	}
+}
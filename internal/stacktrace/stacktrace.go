@@ -2,14 +2,45 @@ package stacktrace
 
 import (
 	"runtime"
+	"slices"
 	"strconv"
 	"strings"
 )
 
 // Get returns a formatted stacktrace starting from the specified number of frames to skip.
 func Get(skip int) string {
-	frames := getFrames(skip)
+	return format(getFrames(skip))
+}
+
+// GetFrom returns a formatted stacktrace starting from the frame of pc,
+// a program counter returned by runtime.Callers on the current stack,
+// e.g. slog.Record.PC. It returns false if pc is not on the stack.
+func GetFrom(pc uintptr) (string, bool) {
+	pcs := callers(0)
+
+	i := slices.Index(pcs, pc)
+	if i < 0 {
+		return "", false
+	}
+
+	return format(runtime.CallersFrames(pcs[i:])), true
+}
+
+// Caller returns the program counter of the frame skip frames above
+// the frame of pc on the current stack, see GetFrom. Inlined calls
+// are separate frames. It returns false if there is no such frame.
+func Caller(pc uintptr, skip int) (uintptr, bool) {
+	pcs := callers(0)
+
+	i := slices.Index(pcs, pc)
+	if i < 0 || i+skip < 0 || i+skip >= len(pcs) {
+		return 0, false
+	}
+
+	return pcs[i+skip], true
+}
 
+func format(frames *runtime.Frames) string {
 	var b strings.Builder
 
 	for {
@@ -30,27 +61,30 @@ func Get(skip int) string {
 const (
 	defaultProgramCounters = 64
 
-	// runtime.Counters, Get and getFrames.
-	baseNestingLevel = 3
+	// runtime.Callers and callers.
+	baseNestingLevel = 2
 
 	pcsExtendFactor = 2
 )
 
 func getFrames(skip int) *runtime.Frames {
+	// Skip getFrames and Get.
+	return runtime.CallersFrames(callers(skip + 2))
+}
+
+// callers returns the program counters of the stack starting from
+// the caller of callers and the specified number of frames to skip.
+func callers(skip int) []uintptr {
 	pcs := make([]uintptr, defaultProgramCounters)
 
 	for {
 		n := runtime.Callers(baseNestingLevel+skip, pcs)
 		if n < cap(pcs) {
-			pcs = pcs[:n]
-
-			break
+			return pcs[:n]
 		}
 
 		pcs = make([]uintptr, len(pcs)*pcsExtendFactor)
 	}
-
-	return runtime.CallersFrames(pcs)
 }
 
 func writeFrame(b *strings.Builder, frame runtime.Frame) {
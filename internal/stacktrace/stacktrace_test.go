@@ -1,6 +1,8 @@
 package stacktrace_test
 
 import (
+	"runtime"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -22,9 +24,52 @@ func Test_Get(t *testing.T) {
 
 	stack := funcWrapper()
 
-	require.Contains(stack, "internal/stacktrace/stacktrace_test.go:17")
+	require.Contains(stack, "internal/stacktrace/stacktrace_test.go:19")
 	require.Contains(stack, "funcWrapper")
 
-	require.NotContains(stack, "internal/stacktrace/stacktrace_test.go:12")
+	require.NotContains(stack, "internal/stacktrace/stacktrace_test.go:14")
 	require.NotContains(stack, "funcNested")
 }
+
+// logged captures the program counter of its caller like slog.Logger
+// and gets the stack from it deeper in the stack like a handler.
+func logged() (uintptr, string, uintptr) {
+	var pcs [1]uintptr
+
+	runtime.Callers(2, pcs[:])
+
+	return handle(pcs[0])
+}
+
+func handle(pc uintptr) (uintptr, string, uintptr) {
+	stack, _ := stacktrace.GetFrom(pc)
+	caller, _ := stacktrace.Caller(pc, 1)
+
+	return pc, stack, caller
+}
+
+func funcLogging() (uintptr, string, uintptr) {
+	return logged()
+}
+
+func Test_GetFrom(t *testing.T) {
+	require := require.New(t)
+
+	pc, stack, caller := funcLogging()
+	require.NotZero(pc)
+
+	require.True(strings.HasPrefix(stack, "github.com/tarantool/go-tlog/internal/stacktrace_test.funcLogging\n"), stack)
+	require.Contains(stack, "Test_GetFrom")
+	require.NotContains(stack, "logged")
+	require.NotContains(stack, "handle")
+
+	frame, _ := runtime.CallersFrames([]uintptr{caller}).Next()
+	require.Equal("github.com/tarantool/go-tlog/internal/stacktrace_test.Test_GetFrom", frame.Function)
+
+	// The call of funcLogging is over.
+	_, ok := stacktrace.GetFrom(pc)
+	require.False(ok)
+
+	_, ok = stacktrace.Caller(pc, 1)
+	require.False(ok)
+}
@@ -1,12 +1,19 @@
 package tlog
 
+import (
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+)
+
 // Level represents logger level.
 type Level int
 
 const (
 	// LevelDefault is the default level. Logger uses LevelInfo as a default one.
 	LevelDefault Level = iota
-	// LevelTrace prints messages up to Debug. Messages up to Debug have stacktraces.
+	// LevelTrace prints messages up to Trace. Messages up to Debug have stacktraces.
 	LevelTrace
 	// LevelDebug prints messages up to Debug. Messages up to Error have stacktraces.
 	LevelDebug
@@ -16,4 +23,204 @@ const (
 	LevelWarn
 	// LevelError prints messages up to Error. Messages up to Error have stacktraces.
 	LevelError
+	// LevelVerbose prints messages up to Verbose, which is between Info
+	// and Debug like in Tarantool. Messages up to Error have stacktraces.
+	LevelVerbose
 )
+
+// Slog levels of records which slog has no levels for.
+const (
+	// LevelTraceSlog is the slog level of trace records, which is below
+	// slog.LevelDebug. Such records are rendered as "TRACE".
+	LevelTraceSlog = slog.LevelDebug - 4
+	// LevelVerboseSlog is the slog level of verbose records, which is
+	// between slog.LevelDebug and slog.LevelInfo. Such records are
+	// rendered as "VERBOSE".
+	LevelVerboseSlog = slog.LevelInfo - 2
+	// LevelPanicSlog is the slog level of Logger.Panic records, which is
+	// above slog.LevelError. Such records are rendered as "PANIC".
+	LevelPanicSlog = slog.LevelError + 4
+	// LevelFatalSlog is the slog level of Logger.Fatal records, which is
+	// the most severe one. Such records are rendered as "FATAL".
+	LevelFatalSlog = slog.LevelError + 8
+)
+
+// slogLevelNames are names of slog levels rendered instead of the default
+// ones like "DEBUG-4".
+var slogLevelNames = map[slog.Level]string{
+	LevelTraceSlog:   "TRACE",
+	LevelVerboseSlog: "VERBOSE",
+	LevelPanicSlog:   "PANIC",
+	LevelFatalSlog:   "FATAL",
+}
+
+// shortLevel returns the single-letter name of level like in Tarantool.
+func shortLevel(level slog.Level) string {
+	switch {
+	case level >= LevelPanicSlog:
+		return "F"
+	case level >= slog.LevelError:
+		return "E"
+	case level >= slog.LevelWarn:
+		return "W"
+	case level >= slog.LevelInfo:
+		return "I"
+	case level >= LevelVerboseSlog:
+		return "V"
+	case level >= slog.LevelDebug:
+		return "D"
+	default:
+		return "T"
+	}
+}
+
+// levelNames are names of levels accepted as per-output modifiers,
+// e.g. "stderr:error", including names of Tarantool log levels.
+var levelNames = map[string]Level{
+	"trace":   LevelTrace,
+	"debug":   LevelDebug,
+	"verbose": LevelVerbose,
+	"info":    LevelInfo,
+	"warn":    LevelWarn,
+	"warning": LevelWarn,
+	"error":   LevelError,
+	// Tarantool levels more severe than error.
+	"crit":     LevelError,
+	"syserror": LevelError,
+	"fatal":    LevelError,
+}
+
+// tarantoolLevels are levels of Tarantool log_level values.
+var tarantoolLevels = [...]Level{
+	0: LevelError, // fatal
+	1: LevelError, // syserror
+	2: LevelError, // error
+	3: LevelError, // crit
+	4: LevelWarn,
+	5: LevelInfo,
+	6: LevelVerbose,
+	7: LevelDebug,
+}
+
+// TarantoolLevel returns the level of Tarantool log_level n, from 0 (fatal)
+// to 7 (debug). Levels more severe than error are LevelError, so error
+// records are printed at them too.
+func TarantoolLevel(n int) (Level, error) {
+	if n < 0 || n >= len(tarantoolLevels) {
+		return 0, fmt.Errorf("tarantool log level must be from 0 to %d, got %d", len(tarantoolLevels)-1, n)
+	}
+
+	return tarantoolLevels[n], nil
+}
+
+// ParseLevel returns the level with the given name, e.g. "debug" or
+// "warning", or the level of a Tarantool log level given by name,
+// e.g. "verbose", or by number, e.g. "6", see TarantoolLevel.
+// Names are case-insensitive.
+func ParseLevel(s string) (Level, error) {
+	if level, ok := parseLevel(s); ok {
+		return level, nil
+	}
+
+	if strings.EqualFold(s, LevelDefault.String()) {
+		return LevelDefault, nil
+	}
+
+	if n, err := strconv.Atoi(s); err == nil {
+		return TarantoolLevel(n)
+	}
+
+	return 0, fmt.Errorf("unknown level %q", s)
+}
+
+// String returns the name of l as accepted in Opts.Path, e.g. "debug".
+func (l Level) String() string {
+	switch l {
+	case LevelDefault:
+		return "default"
+	case LevelTrace:
+		return "trace"
+	case LevelDebug:
+		return "debug"
+	case LevelInfo:
+		return "info"
+	case LevelWarn:
+		return "warn"
+	case LevelError:
+		return "error"
+	case LevelVerbose:
+		return "verbose"
+	default:
+		return fmt.Sprintf("Level(%d)", int(l))
+	}
+}
+
+// valid reports whether l is one of the defined levels.
+func (l Level) valid() bool {
+	return l >= LevelDefault && l <= LevelVerbose
+}
+
+// MarshalText implements encoding.TextMarshaler. It returns the name
+// of l, see String.
+func (l Level) MarshalText() ([]byte, error) {
+	if !l.valid() {
+		return nil, fmt.Errorf("unknown level %d", int(l))
+	}
+
+	return []byte(l.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler, so levels can be
+// read from JSON, YAML and other configuration files. It accepts the
+// same names as ParseLevel.
+func (l *Level) UnmarshalText(text []byte) error {
+	level, err := ParseLevel(string(text))
+	if err != nil {
+		return err
+	}
+
+	*l = level
+
+	return nil
+}
+
+// Set implements flag.Value, so a level can be a command-line flag:
+//
+//	level := tlog.LevelInfo
+//	flag.Var(&level, "log-level", "log level")
+func (l *Level) Set(s string) error {
+	return l.UnmarshalText([]byte(s))
+}
+
+func parseLevel(name string) (Level, bool) {
+	level, ok := levelNames[strings.ToLower(name)]
+	return level, ok
+}
+
+// slogLevel returns the minimum slog level of records printed at l.
+func (l Level) slogLevel() slog.Level {
+	switch l {
+	case LevelTrace:
+		return LevelTraceSlog
+	case LevelDebug:
+		return slog.LevelDebug
+	case LevelVerbose:
+		return LevelVerboseSlog
+	case LevelWarn:
+		return slog.LevelWarn
+	case LevelError:
+		return slog.LevelError
+	default:
+		return slog.LevelInfo
+	}
+}
+
+// stacktraceLevel returns the minimum slog level of records printed
+// with stacktraces at l.
+func (l Level) stacktraceLevel() slog.Level {
+	if l == LevelTrace {
+		return slog.LevelDebug
+	}
+
+	return slog.LevelError
+}
@@ -0,0 +1,379 @@
+package tlog_test
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/tarantool/go-tlog"
+)
+
+func Test_Logger_SetLevel(t *testing.T) {
+	t.Parallel()
+
+	r := require.New(t)
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.log")
+	errorPath := filepath.Join(dir, "error.log")
+
+	l, err := tlog.New(tlog.Opts{Level: tlog.LevelInfo, Path: path + "," + errorPath + ":error"})
+	r.NoError(err)
+	r.Equal(tlog.LevelInfo, l.Level())
+
+	logger := l.Logger().With("component", "x")
+
+	logger.Debug("debug before")
+	logger.Warn("warn before")
+
+	l.SetLevel(tlog.LevelDebug)
+	r.Equal(tlog.LevelDebug, l.Level())
+	r.True(logger.Enabled(context.Background(), slog.LevelDebug))
+
+	logger.Debug("debug after")
+
+	l.SetLevel(tlog.LevelError)
+	r.Equal(tlog.LevelError, l.Level())
+
+	logger.Warn("warn after")
+	logger.Error("error after")
+
+	r.NoError(l.Close())
+
+	logs, err := os.ReadFile(path)
+	r.NoError(err)
+
+	lines := strings.Split(strings.TrimSuffix(string(logs), "\n"), "\n")
+	r.Len(lines, 3)
+	r.Contains(lines[0], "warn before")
+	r.Contains(lines[1], "debug after")
+	r.Contains(lines[2], "error after")
+
+	// Outputs with their own level are not affected.
+	logs, err = os.ReadFile(errorPath)
+	r.NoError(err)
+	r.Equal(1, strings.Count(string(logs), "\n"))
+	r.Contains(string(logs), "error after")
+}
+
+func Test_Logger_SetLevel_Unknown(t *testing.T) {
+	t.Parallel()
+
+	r := require.New(t)
+
+	l, err := tlog.New(tlog.Opts{Level: tlog.LevelWarn, Path: "ring:10"})
+	r.NoError(err)
+
+	defer func() {
+		r.NoError(l.Close())
+	}()
+
+	l.SetLevel(tlog.Level(42))
+	r.Equal(tlog.LevelWarn, l.Level())
+
+	l.SetLevel(tlog.LevelDefault - 1)
+	r.Equal(tlog.LevelWarn, l.Level())
+
+	// The default level is known.
+	l.SetLevel(tlog.LevelDefault)
+	r.Equal(tlog.LevelInfo, l.Level())
+}
+
+func Test_Logger_SetLevel_Stacktrace(t *testing.T) {
+	t.Parallel()
+
+	r := require.New(t)
+
+	path := filepath.Join(t.TempDir(), "app.log")
+
+	l, err := tlog.New(tlog.Opts{Level: tlog.LevelDebug, Path: path})
+	r.NoError(err)
+
+	l.Logger().Debug("debug before")
+
+	l.SetLevel(tlog.LevelTrace)
+	l.Logger().Debug("debug after")
+	l.Trace("trace after")
+
+	r.NoError(l.Close())
+
+	logs, err := os.ReadFile(path)
+	r.NoError(err)
+
+	lines := strings.Split(strings.TrimSuffix(string(logs), "\n"), "\n")
+	r.Len(lines, 3)
+	r.NotContains(lines[0], "stacktrace=")
+	r.Contains(lines[1], "stacktrace=")
+	r.Contains(lines[2], "TRACE")
+	r.NotContains(lines[2], "stacktrace=")
+}
+
+func Test_Logger_SetLevel_Concurrent(t *testing.T) {
+	t.Parallel()
+
+	r := require.New(t)
+
+	path := filepath.Join(t.TempDir(), "app.log")
+
+	l, err := tlog.New(tlog.Opts{Path: path})
+	r.NoError(err)
+
+	const (
+		goroutines = 8
+		records    = 1000
+	)
+
+	var wg sync.WaitGroup
+
+	for range goroutines {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			logger := l.Logger().With("goroutine", true)
+
+			for range records {
+				logger.Debug("debug message")
+				logger.Error("error message")
+			}
+		}()
+	}
+
+	levels := []tlog.Level{tlog.LevelTrace, tlog.LevelDebug, tlog.LevelInfo, tlog.LevelWarn, tlog.LevelError}
+
+	for i := range 1000 {
+		l.SetLevel(levels[i%len(levels)])
+		_ = l.Level()
+	}
+
+	wg.Wait()
+	r.NoError(l.Close())
+
+	logs, err := os.ReadFile(path)
+	r.NoError(err)
+
+	// Error records are logged at any level.
+	r.Equal(goroutines*records, strings.Count(string(logs), "error message"))
+}
+
+func Test_ParseLevel(t *testing.T) {
+	t.Parallel()
+
+	r := require.New(t)
+
+	tests := map[string]tlog.Level{
+		"trace":    tlog.LevelTrace,
+		"DEBUG":    tlog.LevelDebug,
+		"verbose":  tlog.LevelVerbose,
+		"Info":     tlog.LevelInfo,
+		"warn":     tlog.LevelWarn,
+		"error":    tlog.LevelError,
+		"crit":     tlog.LevelError,
+		"syserror": tlog.LevelError,
+		"fatal":    tlog.LevelError,
+		"0":        tlog.LevelError,
+		"3":        tlog.LevelError,
+		"4":        tlog.LevelWarn,
+		"5":        tlog.LevelInfo,
+		"6":        tlog.LevelVerbose,
+		"7":        tlog.LevelDebug,
+	}
+
+	for name, want := range tests {
+		level, err := tlog.ParseLevel(name)
+		r.NoError(err, name)
+		r.Equal(want, level, name)
+	}
+
+	_, err := tlog.ParseLevel("8")
+	r.EqualError(err, "tarantool log level must be from 0 to 7, got 8")
+
+	_, err = tlog.ParseLevel("loud")
+	r.EqualError(err, `unknown level "loud"`)
+
+	_, err = tlog.TarantoolLevel(-1)
+	r.EqualError(err, "tarantool log level must be from 0 to 7, got -1")
+
+	level, err := tlog.TarantoolLevel(6)
+	r.NoError(err)
+	r.Equal("verbose", level.String())
+}
+
+func Test_Logger_Verbose(t *testing.T) {
+	t.Parallel()
+
+	r := require.New(t)
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.log")
+	infoPath := filepath.Join(dir, "info.log")
+
+	l, err := tlog.New(tlog.Opts{Level: tlog.LevelVerbose, Path: path + "," + infoPath + ":info:json"})
+	r.NoError(err)
+
+	l.Logger().Debug("debug message")
+	l.Logger().Log(context.Background(), tlog.LevelVerboseSlog, "verbose message")
+	l.Logger().Info("info message")
+
+	r.NoError(l.Close())
+
+	logs, err := os.ReadFile(path)
+	r.NoError(err)
+
+	lines := strings.Split(strings.TrimSuffix(string(logs), "\n"), "\n")
+	r.Len(lines, 2)
+	r.Contains(lines[0], ` VERBOSE `)
+	r.Contains(lines[1], ` INFO `)
+
+	logs, err = os.ReadFile(infoPath)
+	r.NoError(err)
+	r.NotContains(string(logs), "verbose message")
+	r.Contains(string(logs), "info message")
+}
+
+func Test_Logger_ShortLevels(t *testing.T) {
+	t.Parallel()
+
+	r := require.New(t)
+
+	dir := t.TempDir()
+	textPath := filepath.Join(dir, "app.log")
+	jsonPath := filepath.Join(dir, "app.json")
+
+	l, err := tlog.New(tlog.Opts{
+		Level:       tlog.LevelTrace,
+		Path:        textPath + "," + jsonPath + ":json",
+		ShortLevels: true,
+		Exit:        func(int) {},
+	})
+	r.NoError(err)
+
+	logger := l.Logger()
+	ctx := context.Background()
+
+	l.Trace("message")
+	logger.Debug("message")
+	logger.Log(ctx, tlog.LevelVerboseSlog, "message")
+	logger.Info("message")
+	logger.Warn("message")
+	logger.Error("message")
+	r.Panics(func() { l.Panic("message") })
+	l.Fatal("message")
+
+	logs, err := os.ReadFile(textPath)
+	r.NoError(err)
+
+	lines := strings.Split(strings.TrimSuffix(string(logs), "\n"), "\n")
+	r.Len(lines, 8)
+
+	for i, letter := range []string{"T", "D", "V", "I", "W", "E", "F", "F"} {
+		r.Regexp(`^\S+ `+letter+` \S+level_test.go:\d+ message`, lines[i])
+	}
+
+	logs, err = os.ReadFile(jsonPath)
+	r.NoError(err)
+	r.Contains(string(logs), `"level":"VERBOSE"`)
+	r.Contains(string(logs), `"level":"FATAL"`)
+}
+
+func Test_Level_Text(t *testing.T) {
+	t.Parallel()
+
+	r := require.New(t)
+
+	levels := []tlog.Level{
+		tlog.LevelDefault, tlog.LevelTrace, tlog.LevelDebug, tlog.LevelVerbose,
+		tlog.LevelInfo, tlog.LevelWarn, tlog.LevelError,
+	}
+
+	for _, level := range levels {
+		text, err := level.MarshalText()
+		r.NoError(err)
+
+		var parsed tlog.Level
+
+		r.NoError(parsed.UnmarshalText(text))
+		r.Equal(level, parsed, string(text))
+	}
+
+	_, err := tlog.Level(42).MarshalText()
+	r.EqualError(err, "unknown level 42")
+
+	var level tlog.Level
+
+	r.NoError(level.UnmarshalText([]byte("WARNING")))
+	r.Equal(tlog.LevelWarn, level)
+
+	r.EqualError(level.UnmarshalText([]byte("loud")), `unknown level "loud"`)
+	r.Equal(tlog.LevelWarn, level)
+}
+
+func Test_Level_JSON(t *testing.T) {
+	t.Parallel()
+
+	r := require.New(t)
+
+	var cfg struct {
+		Level  tlog.Level  `json:"level"`
+		Format tlog.Format `json:"format"`
+	}
+
+	r.NoError(json.Unmarshal([]byte(`{"level":"Debug","format":"JSON"}`), &cfg))
+	r.Equal(tlog.LevelDebug, cfg.Level)
+	r.Equal(tlog.FormatJSON, cfg.Format)
+
+	data, err := json.Marshal(cfg)
+	r.NoError(err)
+	r.JSONEq(`{"level":"debug","format":"json"}`, string(data))
+
+	r.Error(json.Unmarshal([]byte(`{"level":"loud"}`), &cfg))
+}
+
+func Test_Level_Flag(t *testing.T) {
+	t.Parallel()
+
+	r := require.New(t)
+
+	level := tlog.LevelInfo
+	format := tlog.FormatText
+
+	flags := flag.NewFlagSet("test", flag.ContinueOnError)
+	flags.SetOutput(io.Discard)
+	flags.Var(&level, "log-level", "log level")
+	flags.Var(&format, "log-format", "log format")
+
+	r.NoError(flags.Parse([]string{"-log-level", "verbose", "-log-format", "json"}))
+	r.Equal(tlog.LevelVerbose, level)
+	r.Equal(tlog.FormatJSON, format)
+	r.Equal("verbose", flags.Lookup("log-level").Value.String())
+
+	r.Error(flags.Parse([]string{"-log-level", "loud"}))
+}
+
+func Test_New_UnknownLevel(t *testing.T) {
+	t.Parallel()
+
+	r := require.New(t)
+
+	_, err := tlog.New(tlog.Opts{Level: tlog.Level(42), Path: "ring:10"})
+	r.EqualError(err, "unknown level 42")
+
+	_, err = tlog.New(tlog.Opts{Format: tlog.Format(42), Path: "ring:10"})
+	r.EqualError(err, "unknown format 42")
+
+	_, err = tlog.New(tlog.Opts{
+		Path:  "ring:10",
+		Fsync: tlog.Fsync{Policy: tlog.FsyncLevel, Level: tlog.Level(-1)},
+	})
+	r.EqualError(err, "unknown fsync level -1")
+}
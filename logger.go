@@ -1,8 +1,14 @@
 package tlog
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"io"
 	"log/slog"
+	"os"
+	"runtime"
+	"sync"
 	"time"
 
 	"github.com/tarantool/go-tlog/internal/outputs"
@@ -14,6 +20,26 @@ import (
 type Logger struct {
 	outputs *outputs.Outputs
 	logger  *slog.Logger
+	// stopReport stops reporting of dropped records, if any.
+	stopReport func()
+	exit       func(code int)
+
+	// mu serializes changes of level.
+	mu    sync.Mutex
+	level Level
+	// restore restores restoreLevel at restoreAt after a temporary
+	// change of level, if any.
+	restore      *time.Timer
+	restoreLevel Level
+	restoreAt    time.Time
+	// logLevel and stacktraceLevel are the slog levels of level,
+	// they are read by the handlers of every record.
+	logLevel        slog.LevelVar
+	stacktraceLevel slog.LevelVar
+	// stacktrace and noStacktrace are Opts.StacktraceLevel and
+	// Opts.DisableStacktrace.
+	stacktrace   Level
+	noStacktrace bool
 }
 
 // Opts are New options.
@@ -23,88 +49,291 @@ type Opts struct {
 	// Format sets log format.
 	Format Format
 	// Path is comma-separated list of log outputs.
-	// Use "stdout" and "stderr" for os streams and file paths for files.
+	// Use "stdout" and "stderr" for os streams, "syslog:..." for syslog,
+	// "tcp://host:port" and "unix:///path" for stream sockets,
+	// "ring:N" for the last N records kept in memory, see DumpRing,
+	// "scheme://..." for sinks added with RegisterSink and file paths
+	// for files. Each output may be followed by its own minimum
+	// level and format, e.g. "stderr:error:text,/var/log/app.log:debug:json".
+	// Outputs without them use Level and Format. "pipe:command" takes
+	// the rest of Path, commas included, so it must be the last output.
 	// Default is "stderr".
 	Path string
+	// Rotation configures rotation of file outputs.
+	Rotation Rotation
+	// Async configures asynchronous logging.
+	Async Async
+	// Network configures network outputs.
+	Network Network
+	// Fsync configures syncing of file outputs to stable storage.
+	Fsync Fsync
+	// NonBlock puts "pipe:", "syslog:", stdout and stderr outputs in
+	// non-blocking mode like log_nonblock of Tarantool: records which
+	// would block are dropped instead of stalling the logging goroutines.
+	// Dropped records are counted and reported like with Async. Note that
+	// stdout and stderr descriptors are usually shared with the parent
+	// process, which sees them in non-blocking mode too, until the logger
+	// is closed.
+	NonBlock bool
+	// DumpRingOnError, if set, receives the records kept by "ring:N"
+	// outputs each time an error record is logged. Records already
+	// dumped this way are not repeated. It requires a "ring:N" output.
+	DumpRingOnError io.Writer
+	// ErrorHandler is called when writing to an output fails, with
+	// the output as given in Path, without level and format. It is called
+	// at most once per second for each output, the error tells how many
+	// failures were suppressed since the previous call. It may be called
+	// while a record is being logged, so it must not log to the same
+	// Logger. Each output is written independently, so a failing output
+	// never prevents records from reaching the others.
+	ErrorHandler func(output string, err error)
+	// StacktraceLevel is the minimum level of records with stacktraces,
+	// e.g. LevelWarn. By default it depends on Level: records up to Debug
+	// have stacktraces at LevelTrace and records up to Error at other
+	// levels. Stacktrace attributes override it for a single record.
+	StacktraceLevel Level
+	// DisableStacktrace disables stacktraces, except for records with
+	// a Stacktrace(true) attribute.
+	DisableStacktrace bool
+	// ShortLevels renders levels in text output as single letters like
+	// Tarantool does: F, E, W, I, V and D, T for trace records. JSON output
+	// keeps full names.
+	ShortLevels bool
+	// Exit is called by Fatal with a non-zero code after the outputs are
+	// closed. Default is os.Exit.
+	Exit func(code int)
+}
+
+// Network configures "tcp://host:port" and "unix:///path" outputs.
+// Records are sent by a background goroutine, so a slow or unavailable
+// peer never blocks logging.
+type Network struct {
+	// Backlog is the maximum number of records kept in memory while
+	// the peer is unavailable. The oldest records are dropped once it is
+	// full. Default is 1024.
+	Backlog int
+	// MaxBackoff is the maximum delay between reconnection attempts.
+	// Default is 10 seconds.
+	MaxBackoff time.Duration
+	// SpoolDir enables an on-disk spool: while the peer is unavailable,
+	// records are appended to segment files in a subdirectory of SpoolDir
+	// for each output instead of Backlog and sent in order once the peer
+	// comes back. Records left on Close are sent by the next run.
+	SpoolDir string
+	// SpoolMaxSize is the maximum size of the spool of an output in bytes.
+	// Once it is reached, the oldest segment is removed, i.e. the oldest
+	// eighth of the records is dropped. Default is 64 MiB.
+	SpoolMaxSize int64
+}
+
+// Rotation configures rotation of file outputs.
+// The zero value disables rotation.
+type Rotation struct {
+	// MaxSize is the size in bytes after which a file is renamed to
+	// a numbered backup ("app.log.1", "app.log.2", ...) and a fresh
+	// file is opened in its place. The highest number is the most recent
+	// backup. Zero disables size-based rotation.
+	MaxSize int64
+	// Interval switches file outputs to a new file at every period
+	// boundary, e.g. time.Hour or 24*time.Hour. Periods are aligned to
+	// the local midnight, so Interval must divide 24 hours.
+	// File paths may contain strftime-style verbs (%Y, %m, %d, %H, %M,
+	// %S), e.g. "/var/log/app-%Y-%m-%d.log", which are expanded with
+	// the current time. Without verbs the file is rotated to a numbered
+	// backup. Zero disables time-based rotation.
+	Interval time.Duration
+	// Symlink is an optional path of a symbolic link that always points
+	// at the current file, e.g. "/var/log/app.log". It requires a single
+	// file output.
+	Symlink string
+	// MaxBackups is the maximum number of rotated files to keep.
+	// Zero keeps all of them.
+	MaxBackups int
+	// MaxAge is the maximum age of rotated files to keep.
+	// Zero keeps rotated files of any age.
+	MaxAge time.Duration
+	// Compress enables gzip compression of rotated files.
+	// Compression and removal of rotated files run in background
+	// and never block logging.
+	Compress bool
+	// ErrorHandler is called when compression or removal of a rotated
+	// file fails. Such failures are ignored if it is nil.
+	ErrorHandler func(path string, err error)
 }
 
 // New creates a new Logger with the given options.
 // It configures level, format and output destinations and returns
 // a ready-to-use logger instance.
 func New(opts Opts) (*Logger, error) {
-	var (
-		logLevel   slog.Level
-		traceLevel slog.Level
-	)
-
-	switch opts.Level {
-	case LevelTrace:
-		traceLevel = slog.LevelDebug
-		logLevel = slog.LevelDebug
-	case LevelDebug:
-		traceLevel = slog.LevelError
-		logLevel = slog.LevelDebug
-	case LevelDefault:
-		fallthrough
-	case LevelInfo:
-		traceLevel = slog.LevelError
-		logLevel = slog.LevelInfo
-	case LevelWarn:
-		traceLevel = slog.LevelError
-		logLevel = slog.LevelWarn
-	case LevelError:
-		traceLevel = slog.LevelError
-		logLevel = slog.LevelError
+	if err := opts.validate(); err != nil {
+		return nil, err
+	}
+
+	logger := &Logger{
+		exit:         opts.Exit,
+		stacktrace:   opts.StacktraceLevel,
+		noStacktrace: opts.DisableStacktrace,
 	}
+	if logger.exit == nil {
+		logger.exit = os.Exit
+	}
+
+	logger.SetLevel(opts.Level)
 
 	if opts.Path == "" {
 		// https://github.com/uber-go/zap/blob/6d482535bdd97f4d97b2f9573ac308f1cf9b574e/config.go#L167C31-L167C37
 		opts.Path = "stderr"
 	}
 
-	outs, err := outputs.New(opts.Path)
+	outs, err := outputs.New(opts.Path, outputs.Opts{
+		MaxSize:  opts.Rotation.MaxSize,
+		Interval: opts.Rotation.Interval,
+		Symlink:  opts.Rotation.Symlink,
+
+		MaxBackups:   opts.Rotation.MaxBackups,
+		MaxAge:       opts.Rotation.MaxAge,
+		Compress:     opts.Rotation.Compress,
+		ErrorHandler: opts.Rotation.ErrorHandler,
+
+		QueueSize: opts.Async.QueueSize,
+		Overflow:  opts.Async.overflow(),
+
+		Backlog:      opts.Network.Backlog,
+		MaxBackoff:   opts.Network.MaxBackoff,
+		SpoolDir:     opts.Network.SpoolDir,
+		SpoolMaxSize: opts.Network.SpoolMaxSize,
+
+		Sync:         opts.Fsync.policy(),
+		SyncLevel:    opts.Fsync.Level.slogLevel(),
+		SyncInterval: opts.Fsync.Interval,
+
+		NonBlock:          opts.NonBlock,
+		WriteErrorHandler: opts.ErrorHandler,
+
+		IsModifier: isModifier,
+	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to create outputs: %w", err)
 	}
 
+	fan, err := newFanoutHandler(outs, &logger.logLevel, opts.Format, opts.handlerOpts)
+	if err != nil {
+		_ = outs.Close()
+
+		return nil, err
+	}
+
+	if opts.DumpRingOnError != nil {
+		if !outs.HasRing() {
+			_ = outs.Close()
+
+			return nil, errors.New("dump of ring on error requires a ring output")
+		}
+
+		fan.fanout.onError = func() {
+			// There is no caller to return the error to.
+			_ = outs.DumpRingNew(opts.DumpRingOnError)
+		}
+	}
+
+	handler := newStacktraceHandler(fan, &logger.stacktraceLevel)
+
+	logger.outputs = outs
+	logger.logger = slog.New(handler)
+
+	if outs.MayDrop() {
+		logger.stopReport = logger.reportDropped(opts.Async.DropReportInterval)
+	}
+
+	return logger, nil
+}
+
+// validate rejects levels and formats out of the defined ones, e.g.
+// converted from unchecked integers.
+func (opts Opts) validate() error {
+	if !opts.Level.valid() {
+		return fmt.Errorf("unknown level %d", int(opts.Level))
+	}
+
+	if !opts.Format.valid() {
+		return fmt.Errorf("unknown format %d", int(opts.Format))
+	}
+
+	if !opts.StacktraceLevel.valid() {
+		return fmt.Errorf("unknown stacktrace level %d", int(opts.StacktraceLevel))
+	}
+
+	if opts.Fsync.Policy == FsyncLevel && !opts.Fsync.Level.valid() {
+		return fmt.Errorf("unknown fsync level %d", int(opts.Fsync.Level))
+	}
+
+	return nil
+}
+
+// handlerOpts returns options of handlers encoding records in format.
+func (opts Opts) handlerOpts(format Format) slog.HandlerOptions {
 	handlerOpts := slog.HandlerOptions{
-		Level:       logLevel,
 		ReplaceAttr: replaceAttr,
 		AddSource:   true,
 	}
 
-	var baseHandler slog.Handler
+	if opts.ShortLevels && format != FormatJSON {
+		handlerOpts.ReplaceAttr = replaceAttrShort
+	}
 
-	switch opts.Format {
-	case FormatDefault:
-		fallthrough
-	case FormatText:
-		baseHandler = slogcustom.NewTextHandler(outs, &slogcustom.HandlerOptions{
-			HandlerOptions:  handlerOpts,
+	return handlerOpts
+}
+
+// newHandler creates a handler encoding records in the given format.
+func newHandler(format Format, w io.Writer, opts slog.HandlerOptions) slog.Handler {
+	switch format {
+	case FormatJSON:
+		return slog.NewJSONHandler(w, &opts)
+	default:
+		return slogcustom.NewTextHandler(w, &slogcustom.HandlerOptions{
+			HandlerOptions:  opts,
 			OmitBuiltinKeys: true,
 		})
-	case FormatJSON:
-		baseHandler = slog.NewJSONHandler(outs, &handlerOpts)
 	}
-
-	handler := newStacktraceHandler(baseHandler, traceLevel)
-	l := slog.New(handler)
-
-	return &Logger{
-		outputs: outs,
-		logger:  l,
-	}, nil
 }
 
 func replaceAttr(group []string, a slog.Attr) slog.Attr {
 	switch a.Key {
 	case slog.TimeKey:
 		return replaceTime(group, a)
+	case slog.LevelKey:
+		return replaceLevel(group, a)
 	default:
 		return a
 	}
 }
 
+// replaceAttrShort is replaceAttr rendering levels as single letters.
+func replaceAttrShort(group []string, a slog.Attr) slog.Attr {
+	if a.Key != slog.LevelKey {
+		return replaceAttr(group, a)
+	}
+
+	if level, ok := a.Value.Any().(slog.Level); ok {
+		a.Value = slog.StringValue(shortLevel(level))
+	}
+
+	return a
+}
+
+func replaceLevel(_ []string, a slog.Attr) slog.Attr {
+	level, ok := a.Value.Any().(slog.Level)
+	if !ok {
+		return a
+	}
+
+	if name, ok := slogLevelNames[level]; ok {
+		a.Value = slog.StringValue(name)
+	}
+
+	return a
+}
+
 func replaceTime(_ []string, a slog.Attr) slog.Attr {
 	t := a.Value.Time()
 
@@ -119,7 +348,147 @@ func (l *Logger) Logger() *slog.Logger {
 	return l.logger
 }
 
-// Close flushes all pending log entries and closes all opened outputs.
+// SetLevel changes the minimum level of the outputs without their own
+// level in Opts.Path and, unless Opts.StacktraceLevel or
+// Opts.DisableStacktrace is set, the minimum level of records with
+// stacktraces. It is safe to call concurrently with logging. It cancels a pending
+// restore of the level set with LevelHandler. Unknown levels are ignored.
+func (l *Logger) SetLevel(level Level) {
+	if !level.valid() {
+		return
+	}
+
+	l.setLevelFor(level, 0)
+}
+
+// setLevelFor sets the level and, if d is positive, restores the
+// previous one after d. The previous level of a temporary change is
+// the one it is going to be restored to.
+func (l *Logger) setLevelFor(level Level, d time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	previous := l.level
+	if l.restore != nil {
+		l.restore.Stop()
+		l.restore = nil
+		previous = l.restoreLevel
+	}
+
+	l.setLevel(level)
+
+	if d <= 0 {
+		return
+	}
+
+	var timer *time.Timer
+
+	timer = time.AfterFunc(d, func() {
+		l.mu.Lock()
+		defer l.mu.Unlock()
+
+		// The restore was canceled after the timer had fired.
+		if l.restore != timer {
+			return
+		}
+
+		l.restore = nil
+		l.setLevel(l.restoreLevel)
+	})
+
+	l.restore = timer
+	l.restoreLevel = previous
+	l.restoreAt = time.Now().Add(d)
+}
+
+// setLevel sets the level, l.mu must be held.
+func (l *Logger) setLevel(level Level) {
+	if level == LevelDefault {
+		level = LevelInfo
+	}
+
+	l.level = level
+	l.logLevel.Set(level.slogLevel())
+	l.stacktraceLevel.Set(l.stacktraceFrom(level))
+}
+
+// stacktraceFrom returns the minimum slog level of records with
+// stacktraces at level.
+func (l *Logger) stacktraceFrom(level Level) slog.Level {
+	switch {
+	case l.noStacktrace:
+		return noStacktraceLevel
+	case l.stacktrace != LevelDefault:
+		return l.stacktrace.slogLevel()
+	default:
+		return level.stacktraceLevel()
+	}
+}
+
+// Level returns the level set with Opts.Level or SetLevel, LevelInfo
+// for LevelDefault.
+func (l *Logger) Level() Level {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.level
+}
+
+// Reopen closes and reopens all file outputs at their paths, leaving
+// stdout and stderr alone. Use it after an external tool like logrotate
+// has moved the files away. Records logged concurrently are not lost.
+func (l *Logger) Reopen() error {
+	return l.outputs.Reopen()
+}
+
+// DumpRing writes the last records kept by "ring:N" outputs to w, oldest
+// first, e.g. to see debug records preceding a crash. Records are kept
+// after Close, so they can be dumped after it too.
+func (l *Logger) DumpRing(w io.Writer) error {
+	return l.outputs.DumpRing(w)
+}
+
+// OutputErrors returns the number of failed writes for each output
+// of Opts.Path, without level and format.
+func (l *Logger) OutputErrors() map[string]uint64 {
+	list := l.outputs.List()
+	errs := make(map[string]uint64, len(list))
+
+	for _, out := range list {
+		errs[out.Path] += out.Errors()
+	}
+
+	return errs
+}
+
+// Close flushes all pending log entries, syncs file outputs and closes
+// all opened outputs. With asynchronous logging, it waits for queued
+// records to be written.
 func (l *Logger) Close() error {
+	if l.stopReport != nil {
+		l.stopReport()
+		l.stopReport = nil
+	}
+
 	return l.outputs.Close()
 }
+
+// logAt logs a record of the given level. It must be called directly by
+// exported logging functions, so that the record gets the source of their
+// caller, the same way slog.Logger does it.
+func logAt(ctx context.Context, logger *slog.Logger, level slog.Level, msg string, args ...any) {
+	if !logger.Enabled(ctx, level) {
+		return
+	}
+
+	var pcs [1]uintptr
+
+	// Skip runtime.Callers, logAt and the exported function.
+	runtime.Callers(3, pcs[:])
+
+	record := slog.NewRecord(time.Now(), level, msg, pcs[0])
+	record.Add(args...)
+
+	// The error is ignored, the same way slog.Logger does it.
+	_ = logger.Handler().Handle(ctx, record)
+}
@@ -1,10 +1,16 @@
 package tlog_test
 
 import (
+	"context"
+	"encoding/json"
 	"log/slog"
+	"net"
 	"os"
 	"path/filepath"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 
@@ -205,3 +211,253 @@ func Test_Logger(t *testing.T) {
 		})
 	}
 }
+
+func Test_Logger_Rotation_Concurrent(t *testing.T) {
+	t.Parallel()
+
+	r := require.New(t)
+
+	path := filepath.Join(t.TempDir(), "Test_Logger_Rotation_Concurrent.log")
+
+	l, err := tlog.New(tlog.Opts{
+		Format:   tlog.FormatJSON,
+		Path:     path,
+		Rotation: tlog.Rotation{MaxSize: 4096},
+	})
+	r.NoError(err)
+
+	const (
+		goroutines = 8
+		records    = 100
+	)
+
+	var wg sync.WaitGroup
+
+	for i := range goroutines {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for j := range records {
+				l.Logger().Info("concurrent", "goroutine", i, "record", j)
+			}
+		}()
+	}
+
+	wg.Wait()
+	r.NoError(l.Close())
+
+	names, err := filepath.Glob(path + "*")
+	r.NoError(err)
+	r.Greater(len(names), 1)
+
+	count := 0
+
+	for _, name := range names {
+		data, err := os.ReadFile(name)
+		r.NoError(err)
+
+		for _, line := range strings.Split(strings.TrimSuffix(string(data), "\n"), "\n") {
+			var record map[string]any
+
+			r.NoError(json.Unmarshal([]byte(line), &record), line)
+			r.Equal("concurrent", record["msg"])
+
+			count++
+		}
+	}
+
+	r.Equal(goroutines*records, count)
+}
+
+func Test_Logger_Syslog(t *testing.T) {
+	t.Parallel()
+
+	r := require.New(t)
+
+	// Socket paths are limited to ~100 bytes, t.TempDir may be longer.
+	dir, err := os.MkdirTemp("", "tlog")
+	r.NoError(err)
+
+	defer func() {
+		_ = os.RemoveAll(dir)
+	}()
+
+	socket := filepath.Join(dir, "log.sock")
+
+	conn, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Name: socket, Net: "unixgram"})
+	r.NoError(err)
+
+	defer func() {
+		_ = conn.Close()
+	}()
+
+	l, err := tlog.New(tlog.Opts{
+		Level: tlog.LevelDebug,
+		Path:  "syslog:server=" + socket + ",identity=tlog_test,facility=user:warn",
+	})
+	r.NoError(err)
+
+	defer func() {
+		_ = l.Close()
+	}()
+
+	l.Logger().Info("my info message")
+	l.Logger().Error("my error message")
+
+	r.NoError(conn.SetReadDeadline(time.Now().Add(5 * time.Second)))
+
+	buf := make([]byte, 64<<10)
+	n, err := conn.Read(buf)
+	r.NoError(err)
+
+	// user * 8 + error, info is filtered out by the output level.
+	msg := string(buf[:n])
+	r.True(strings.HasPrefix(msg, "<11>"), msg)
+	r.Contains(msg, "tlog_test[")
+	r.Contains(msg, "ERROR")
+	r.Contains(msg, "my error message")
+	r.False(strings.HasSuffix(msg, "\n"))
+}
+
+func Test_Logger_TCP_Unavailable(t *testing.T) {
+	t.Parallel()
+
+	r := require.New(t)
+
+	// Reserve an address with nobody listening on it.
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	r.NoError(err)
+
+	addr := listener.Addr().String()
+	r.NoError(listener.Close())
+
+	l, err := tlog.New(tlog.Opts{
+		Path:    "tcp://" + addr,
+		Network: tlog.Network{Backlog: 10},
+	})
+	r.NoError(err)
+
+	start := time.Now()
+
+	for i := range 100 {
+		record := slog.NewRecord(time.Now(), slog.LevelInfo, "unsent", 0)
+		record.Add("i", i)
+
+		r.NoError(l.Logger().Handler().Handle(context.Background(), record))
+	}
+
+	r.NoError(l.Close())
+	r.Less(time.Since(start), time.Second)
+}
+
+func Test_Logger_TarantoolPaths(t *testing.T) {
+	t.Parallel()
+
+	r := require.New(t)
+
+	dir := t.TempDir()
+	filePath := filepath.Join(dir, "file.log")
+	pipePath := filepath.Join(dir, "pipe.log")
+
+	l, err := tlog.New(tlog.Opts{
+		Path: "file:" + filePath + ",pipe:cat > " + pipePath + ":error",
+	})
+	r.NoError(err)
+
+	l.Logger().Info("info message")
+	l.Logger().Error("error message")
+	r.NoError(l.Close())
+
+	fileLogs, err := os.ReadFile(filePath)
+	r.NoError(err)
+	r.Contains(string(fileLogs), "info message")
+	r.Contains(string(fileLogs), "error message")
+
+	pipeLogs, err := os.ReadFile(pipePath)
+	r.NoError(err)
+	r.NotContains(string(pipeLogs), "info message")
+	r.Contains(string(pipeLogs), "error message")
+}
+
+func Test_Logger_BadTarantoolPaths(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		path string
+		err  string
+	}{
+		{"file:", "file destination must be file:/path"},
+		{"pipe:", "pipe destination must be pipe:command"},
+		{"syslog:identity", `syslog option "identity" must be key=value`},
+		{"syslog:facility=local9", `unknown syslog facility "local9"`},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.path, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := tlog.New(tlog.Opts{Path: tc.path})
+			require.ErrorContains(t, err, tc.err)
+		})
+	}
+}
+
+func Test_Logger_TCP_Spool(t *testing.T) {
+	t.Parallel()
+
+	r := require.New(t)
+
+	// Reserve an address with nobody listening on it.
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	r.NoError(err)
+
+	addr := listener.Addr().String()
+	r.NoError(listener.Close())
+
+	l, err := tlog.New(tlog.Opts{
+		Path:   "tcp://" + addr,
+		Format: tlog.FormatJSON,
+		Network: tlog.Network{
+			Backlog:    1,
+			MaxBackoff: 50 * time.Millisecond,
+			SpoolDir:   t.TempDir(),
+		},
+	})
+	r.NoError(err)
+
+	for i := range 10 {
+		l.Logger().Info("spooled", "i", i)
+	}
+
+	// The collector comes back.
+	listener, err = net.Listen("tcp", addr)
+	r.NoError(err)
+
+	defer func() {
+		_ = listener.Close()
+	}()
+
+	conn, err := listener.Accept()
+	r.NoError(err)
+
+	defer func() {
+		_ = conn.Close()
+	}()
+
+	r.NoError(conn.SetReadDeadline(time.Now().Add(5 * time.Second)))
+
+	decoder := json.NewDecoder(conn)
+
+	for i := range 10 {
+		var record struct {
+			I int `json:"i"`
+		}
+
+		r.NoError(decoder.Decode(&record))
+		r.Equal(i, record.I)
+	}
+
+	r.NoError(l.Close())
+}
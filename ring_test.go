@@ -0,0 +1,85 @@
+package tlog_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/tarantool/go-tlog"
+)
+
+func Test_Logger_Ring(t *testing.T) {
+	t.Parallel()
+
+	r := require.New(t)
+
+	path := filepath.Join(t.TempDir(), "app.log")
+
+	l, err := tlog.New(tlog.Opts{
+		Level: tlog.LevelInfo,
+		Path:  path + ",ring:2:debug:json",
+	})
+	r.NoError(err)
+
+	l.Logger().Debug("first debug")
+	l.Logger().Debug("second debug")
+	l.Logger().Info("info message")
+
+	r.NoError(l.Close())
+
+	logs, err := os.ReadFile(path)
+	r.NoError(err)
+	r.NotContains(string(logs), "debug")
+	r.Contains(string(logs), "info message")
+
+	var buf bytes.Buffer
+
+	r.NoError(l.DumpRing(&buf))
+	r.NotContains(buf.String(), "first debug")
+	r.Contains(buf.String(), `"msg":"second debug"`)
+	r.Contains(buf.String(), `"msg":"info message"`)
+}
+
+func Test_Logger_Ring_DumpOnError(t *testing.T) {
+	t.Parallel()
+
+	r := require.New(t)
+
+	var dump bytes.Buffer
+
+	l, err := tlog.New(tlog.Opts{
+		Level:           tlog.LevelError,
+		Path:            "/dev/null,ring:100:debug",
+		DumpRingOnError: &dump,
+	})
+	r.NoError(err)
+
+	l.Logger().Debug("debug message")
+	r.Empty(dump.String())
+
+	l.Logger().Error("first error")
+	r.Contains(dump.String(), "debug message")
+	r.Contains(dump.String(), "first error")
+
+	dump.Reset()
+	l.Logger().Warn("warn message")
+	l.Logger().Error("second error")
+	r.NotContains(dump.String(), "first error")
+	r.Contains(dump.String(), "warn message")
+	r.Contains(dump.String(), "second error")
+
+	r.NoError(l.Close())
+}
+
+func Test_Logger_Ring_DumpOnErrorWithoutRing(t *testing.T) {
+	t.Parallel()
+
+	_, err := tlog.New(tlog.Opts{
+		Path:            "/dev/null",
+		DumpRingOnError: &bytes.Buffer{},
+	})
+	require.EqualError(t, err, "dump of ring on error requires a ring output")
+}
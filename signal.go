@@ -0,0 +1,48 @@
+package tlog
+
+import (
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+)
+
+// ReopenOnSIGHUP reopens file outputs each time the process receives
+// SIGHUP, which is what logrotate sends in its "create" mode. Reopen
+// failures are logged by the logger itself. The returned function stops
+// handling the signal.
+func (l *Logger) ReopenOnSIGHUP() (stop func()) {
+	signals := make(chan os.Signal, 1)
+	done := make(chan struct{})
+
+	signal.Notify(signals, syscall.SIGHUP)
+
+	var wg sync.WaitGroup
+
+	wg.Add(1)
+
+	go func() {
+		defer wg.Done()
+
+		for {
+			select {
+			case <-done:
+				return
+			case <-signals:
+				if err := l.Reopen(); err != nil {
+					l.logger.Error("failed to reopen outputs", "err", err)
+				}
+			}
+		}
+	}()
+
+	var once sync.Once
+
+	return func() {
+		once.Do(func() {
+			signal.Stop(signals)
+			close(done)
+			wg.Wait()
+		})
+	}
+}
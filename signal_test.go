@@ -0,0 +1,53 @@
+package tlog_test
+
+import (
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/tarantool/go-tlog"
+)
+
+func Test_Logger_ReopenOnSIGHUP(t *testing.T) {
+	r := require.New(t)
+
+	path := filepath.Join(t.TempDir(), "app.log")
+
+	l, err := tlog.New(tlog.Opts{Path: path})
+	r.NoError(err)
+
+	defer func() {
+		_ = l.Close()
+	}()
+
+	stop := l.ReopenOnSIGHUP()
+	defer stop()
+
+	l.Logger().Info("before rotation")
+
+	r.NoError(os.Rename(path, path+".1"))
+
+	process, err := os.FindProcess(os.Getpid())
+	r.NoError(err)
+	r.NoError(process.Signal(syscall.SIGHUP))
+
+	r.Eventually(func() bool {
+		_, err := os.Stat(path)
+		return err == nil
+	}, time.Second, 10*time.Millisecond)
+
+	l.Logger().Info("after rotation")
+
+	old, err := os.ReadFile(path + ".1")
+	r.NoError(err)
+	r.Contains(string(old), "before rotation")
+	r.NotContains(string(old), "after rotation")
+
+	current, err := os.ReadFile(path)
+	r.NoError(err)
+	r.Contains(string(current), "after rotation")
+}
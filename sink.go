@@ -0,0 +1,19 @@
+package tlog
+
+import (
+	"io"
+	"net/url"
+
+	"github.com/tarantool/go-tlog/internal/outputs"
+)
+
+// RegisterSink registers a factory of outputs for "scheme://..." entries
+// of Opts.Path. The factory is called by New with the parsed entry, level
+// and format modifiers excluded. Built-in schemes are "file", "pipe",
+// "ring", "syslog", "tcp" and "unix".
+//
+// It returns an error if the scheme is invalid or already registered.
+// Entries with an unregistered scheme make New fail.
+func RegisterSink(scheme string, factory func(u *url.URL) (io.WriteCloser, error)) error {
+	return outputs.RegisterSink(scheme, factory)
+}
@@ -0,0 +1,91 @@
+package tlog_test
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/url"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/tarantool/go-tlog"
+)
+
+// memorySink is a custom sink collecting records in memory.
+type memorySink struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (s *memorySink) Write(p []byte) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.buf.Write(p)
+}
+
+func (s *memorySink) Close() error {
+	return nil
+}
+
+func (s *memorySink) String() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.buf.String()
+}
+
+// schemes is the number of sinks registered by tests.
+var schemes atomic.Int64
+
+// registerSink registers factory with a unique scheme, so tests can be
+// run repeatedly, and returns the scheme.
+func registerSink(t *testing.T, factory func(u *url.URL) (io.WriteCloser, error)) string {
+	t.Helper()
+
+	scheme := fmt.Sprintf("tlog-test-%d", schemes.Add(1))
+	require.NoError(t, tlog.RegisterSink(scheme, factory))
+
+	return scheme
+}
+
+func Test_Logger_RegisterSink(t *testing.T) {
+	t.Parallel()
+
+	sink := &memorySink{}
+
+	scheme := registerSink(t, func(u *url.URL) (io.WriteCloser, error) {
+		require.Equal(t, "memory", u.Host)
+
+		return sink, nil
+	})
+
+	logger, err := tlog.New(tlog.Opts{
+		Level:  tlog.LevelInfo,
+		Format: tlog.FormatJSON,
+		Path:   scheme + "://memory:warn",
+	})
+	require.NoError(t, err)
+
+	logger.Logger().Info("skipped")
+	logger.Logger().Warn("hello")
+	require.NoError(t, logger.Close())
+
+	require.NotContains(t, sink.String(), "skipped")
+	require.Contains(t, sink.String(), `"msg":"hello"`)
+
+	err = tlog.RegisterSink(scheme, func(*url.URL) (io.WriteCloser, error) {
+		return &memorySink{}, nil
+	})
+	require.EqualError(t, err, `sink for scheme "`+scheme+`" is already registered`)
+}
+
+func Test_Logger_UnknownScheme(t *testing.T) {
+	t.Parallel()
+
+	_, err := tlog.New(tlog.Opts{Path: "tlog-test-unknown://x"})
+	require.ErrorContains(t, err, `unknown scheme "tlog-test-unknown"`)
+}
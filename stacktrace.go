@@ -3,17 +3,57 @@ package tlog
 import (
 	"context"
 	"log/slog"
+	"math"
+	"slices"
 
 	"github.com/tarantool/go-tlog/internal/stacktrace"
 )
 
+// noStacktraceLevel is the stacktrace level of a handler with disabled
+// stacktraces, no record has such a level.
+const noStacktraceLevel = slog.Level(math.MaxInt)
+
+const stacktraceKey = "stacktrace"
+
+// stacktraceMode is the value of Stacktrace attributes.
+type stacktraceMode bool
+
+// Stacktrace returns an attribute which forces (true) or suppresses
+// (false) the stacktrace of the record it is logged with, or of all
+// records of a logger derived with With, regardless of
+// Opts.StacktraceLevel. The attribute itself is not printed:
+//
+//	logger.Warn("slow query", "query", query, tlog.Stacktrace(true))
+func Stacktrace(enabled bool) slog.Attr {
+	return slog.Any(stacktraceKey, stacktraceMode(enabled))
+}
+
+// stacktraceHandler adds stacktraces to records of Handler. It wraps
+// the handlers derived with WithAttrs and WithGroup too, so that loggers
+// derived with With and WithGroup keep stacktraces.
 type stacktraceHandler struct {
 	slog.Handler
 
-	fromLevel slog.Level
+	// ungrouped is Handler before the first WithGroup. It is derived
+	// again with groups to add stacktraces at the top level rather than
+	// to the open group.
+	ungrouped slog.Handler
+	groups    []derivation
+
+	fromLevel slog.Leveler
+	// mode is set by a Stacktrace attribute given to WithAttrs.
+	mode    stacktraceMode
+	hasMode bool
+}
+
+// derivation is a group given to WithGroup, if not empty, or otherwise
+// attributes given to WithAttrs.
+type derivation struct {
+	group string
+	attrs []slog.Attr
 }
 
-func newStacktraceHandler(h slog.Handler, fromLevel slog.Level) stacktraceHandler {
+func newStacktraceHandler(h slog.Handler, fromLevel slog.Leveler) stacktraceHandler {
 	return stacktraceHandler{
 		Handler:   h,
 		fromLevel: fromLevel,
@@ -21,13 +61,146 @@ func newStacktraceHandler(h slog.Handler, fromLevel slog.Level) stacktraceHandle
 }
 
 // Strip stacktraceHandler.Handle, slog.(*Logger).log and
-// slog.(*Logger).<Level>.
+// slog.(*Logger).<Level> if the record has no PC on the stack.
 var internalsStripLevel = 3
 
 func (h stacktraceHandler) Handle(ctx context.Context, record slog.Record) error {
-	if record.Level >= h.fromLevel {
-		record.Add("stacktrace", stacktrace.Get(internalsStripLevel))
+	add := record.Level >= h.fromLevel.Level()
+	if h.hasMode {
+		add = bool(h.mode)
+	}
+
+	if mode, ok := recordStacktraceMode(record); ok {
+		add = bool(mode)
+		record = withoutStacktraceMode(record)
+	}
+
+	if !add {
+		return h.Handler.Handle(ctx, record)
+	}
+
+	stack := slog.String(stacktraceKey, recordStacktrace(record))
+	if len(h.groups) == 0 {
+		record.AddAttrs(stack)
+
+		return h.Handler.Handle(ctx, record)
 	}
 
-	return h.Handler.Handle(ctx, record)
+	return h.withTopAttr(stack).Handle(ctx, record)
+}
+
+// withTopAttr returns Handler with a added before the groups. Handlers
+// are derived for each record, but only records with stacktraces of
+// loggers derived with WithGroup pay for it.
+func (h stacktraceHandler) withTopAttr(a slog.Attr) slog.Handler {
+	handler := h.ungrouped.WithAttrs([]slog.Attr{a})
+
+	for _, d := range h.groups {
+		if d.group != "" {
+			handler = handler.WithGroup(d.group)
+		} else {
+			handler = handler.WithAttrs(d.attrs)
+		}
+	}
+
+	return handler
+}
+
+// recordStacktrace returns the stacktrace starting from the frame which
+// has logged the record, the same as its source, regardless of the way
+// it has been logged.
+func recordStacktrace(record slog.Record) string {
+	if stack, ok := stacktrace.GetFrom(record.PC); ok {
+		return stack
+	}
+
+	// The record has no PC or it has been logged by another goroutine,
+	// also strip recordStacktrace.
+	return stacktrace.Get(internalsStripLevel + 1)
+}
+
+func (h stacktraceHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	kept := make([]slog.Attr, 0, len(attrs))
+
+	for _, a := range attrs {
+		if mode, ok := attrStacktraceMode(a); ok {
+			h.mode, h.hasMode = mode, true
+
+			continue
+		}
+
+		kept = append(kept, a)
+	}
+
+	if len(kept) == 0 {
+		return h
+	}
+
+	h.Handler = h.Handler.WithAttrs(kept)
+	if len(h.groups) > 0 {
+		h.groups = append(slices.Clip(h.groups), derivation{attrs: kept})
+	}
+
+	return h
+}
+
+func (h stacktraceHandler) WithGroup(name string) slog.Handler {
+	// An empty group is ignored by slog.
+	if name == "" {
+		return h
+	}
+
+	if len(h.groups) == 0 {
+		h.ungrouped = h.Handler
+	}
+
+	h.Handler = h.Handler.WithGroup(name)
+	h.groups = append(slices.Clip(h.groups), derivation{group: name})
+
+	return h
+}
+
+// recordStacktraceMode returns the value of the last Stacktrace attribute
+// of record, if any.
+func recordStacktraceMode(record slog.Record) (stacktraceMode, bool) {
+	var (
+		mode  stacktraceMode
+		found bool
+	)
+
+	record.Attrs(func(a slog.Attr) bool {
+		if m, ok := attrStacktraceMode(a); ok {
+			mode, found = m, true
+		}
+
+		return true
+	})
+
+	return mode, found
+}
+
+func attrStacktraceMode(a slog.Attr) (stacktraceMode, bool) {
+	if a.Value.Kind() != slog.KindAny {
+		return false, false
+	}
+
+	mode, ok := a.Value.Any().(stacktraceMode)
+
+	return mode, ok
+}
+
+// withoutStacktraceMode returns a copy of record without Stacktrace
+// attributes.
+func withoutStacktraceMode(record slog.Record) slog.Record {
+	stripped := slog.NewRecord(record.Time, record.Level, record.Message, record.PC)
+
+	record.Attrs(func(a slog.Attr) bool {
+		if _, ok := attrStacktraceMode(a); !ok {
+			stripped.AddAttrs(a)
+		}
+
+		return true
+	})
+
+	return stripped
 }
@@ -0,0 +1,246 @@
+package tlog_test
+
+import (
+	"encoding/json"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/tarantool/go-tlog"
+)
+
+// readLogLines returns the lines of a log file.
+func readLogLines(t *testing.T, path string) []string {
+	t.Helper()
+
+	logs, err := os.ReadFile(path)
+	require.NoError(t, err)
+
+	return strings.Split(strings.TrimSuffix(string(logs), "\n"), "\n")
+}
+
+func Test_Logger_StacktraceLevel(t *testing.T) {
+	t.Parallel()
+
+	r := require.New(t)
+
+	path := filepath.Join(t.TempDir(), "app.log")
+
+	l, err := tlog.New(tlog.Opts{Level: tlog.LevelDebug, StacktraceLevel: tlog.LevelWarn, Path: path})
+	r.NoError(err)
+
+	l.Logger().Info("info message")
+	l.Logger().Warn("warn message")
+
+	// SetLevel keeps the stacktrace level.
+	l.SetLevel(tlog.LevelTrace)
+	l.Logger().Debug("debug message")
+	l.Logger().Warn("warn message")
+
+	r.NoError(l.Close())
+
+	lines := readLogLines(t, path)
+	r.Len(lines, 4)
+	r.NotContains(lines[0], "stacktrace=")
+	r.Contains(lines[1], "stacktrace=")
+	r.NotContains(lines[2], "stacktrace=")
+	r.Contains(lines[3], "stacktrace=")
+}
+
+func Test_Logger_DisableStacktrace(t *testing.T) {
+	t.Parallel()
+
+	r := require.New(t)
+
+	path := filepath.Join(t.TempDir(), "app.log")
+
+	l, err := tlog.New(tlog.Opts{Level: tlog.LevelTrace, DisableStacktrace: true, Path: path})
+	r.NoError(err)
+
+	l.Logger().Debug("debug message")
+	l.Logger().Error("error message")
+	l.Logger().Info("forced message", tlog.Stacktrace(true))
+
+	r.NoError(l.Close())
+
+	lines := readLogLines(t, path)
+	r.Len(lines, 3)
+	r.NotContains(lines[0], "stacktrace=")
+	r.NotContains(lines[1], "stacktrace=")
+	r.Contains(lines[2], `"forced message" stacktrace="github.com/tarantool/go-tlog_test.Test_Logger_DisableStacktrace`)
+	r.Equal(1, strings.Count(lines[2], "stacktrace="))
+}
+
+func Test_Logger_StacktraceAttr(t *testing.T) {
+	t.Parallel()
+
+	r := require.New(t)
+
+	path := filepath.Join(t.TempDir(), "app.json")
+
+	l, err := tlog.New(tlog.Opts{Format: tlog.FormatJSON, Path: path})
+	r.NoError(err)
+
+	l.Logger().Error("suppressed", "key", "value", tlog.Stacktrace(false))
+	l.Logger().Warn("forced", tlog.Stacktrace(true), "key", "value")
+	// The last attribute wins.
+	l.Logger().Info("last", tlog.Stacktrace(true), tlog.Stacktrace(false))
+
+	r.NoError(l.Close())
+
+	lines := readLogLines(t, path)
+	r.Len(lines, 3)
+
+	records := make([]map[string]any, len(lines))
+	for i, line := range lines {
+		r.NoError(json.Unmarshal([]byte(line), &records[i]))
+	}
+
+	r.NotContains(records[0], "stacktrace")
+	r.Equal("value", records[0]["key"])
+
+	r.Contains(records[1]["stacktrace"], "Test_Logger_StacktraceAttr")
+	r.Equal("value", records[1]["key"])
+
+	r.NotContains(records[2], "stacktrace")
+}
+
+func Test_New_UnknownStacktraceLevel(t *testing.T) {
+	t.Parallel()
+
+	_, err := tlog.New(tlog.Opts{StacktraceLevel: tlog.Level(42), Path: "ring:10"})
+	require.EqualError(t, err, "unknown stacktrace level 42")
+}
+
+func Test_Logger_Stacktrace_Derived(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		derive func(*slog.Logger) *slog.Logger
+		want   []string
+	}{
+		{
+			name:   "with",
+			derive: func(l *slog.Logger) *slog.Logger { return l.With("component", "x") },
+			want:   []string{"component=x", " stacktrace="},
+		},
+		{
+			name:   "with group",
+			derive: func(l *slog.Logger) *slog.Logger { return l.WithGroup("req") },
+			want:   []string{"req.key=value", " stacktrace="},
+		},
+		{
+			name: "with group with",
+			derive: func(l *slog.Logger) *slog.Logger {
+				return l.WithGroup("req").With("id", 1)
+			},
+			want: []string{"req.id=1", "req.key=value", " stacktrace="},
+		},
+		{
+			name: "with with group with",
+			derive: func(l *slog.Logger) *slog.Logger {
+				return l.With("component", "x").WithGroup("req").With("id", 1).With("user", "u")
+			},
+			want: []string{"component=x stacktrace=", "req.id=1", "req.user=u", "req.key=value"},
+		},
+		{
+			name: "nested groups",
+			derive: func(l *slog.Logger) *slog.Logger {
+				return l.WithGroup("a").With("x", 1).WithGroup("b")
+			},
+			want: []string{"a.x=1", "a.b.key=value", " stacktrace="},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			r := require.New(t)
+
+			path := filepath.Join(t.TempDir(), "app.log")
+
+			l, err := tlog.New(tlog.Opts{Path: path})
+			r.NoError(err)
+
+			logger := tt.derive(l.Logger())
+			logger.Error("error message", "key", "value")
+			logger.Info("info message")
+
+			r.NoError(l.Close())
+
+			lines := readLogLines(t, path)
+			r.Len(lines, 2)
+
+			for _, want := range tt.want {
+				r.Contains(lines[0], want)
+			}
+
+			r.Contains(lines[0], " stacktrace=\"github.com/tarantool/go-tlog_test.Test_Logger_Stacktrace_Derived")
+			r.NotContains(lines[0], ".stacktrace=")
+			r.NotContains(lines[1], "stacktrace=")
+		})
+	}
+}
+
+func Test_Logger_Stacktrace_DerivedJSON(t *testing.T) {
+	t.Parallel()
+
+	r := require.New(t)
+
+	path := filepath.Join(t.TempDir(), "app.json")
+
+	l, err := tlog.New(tlog.Opts{Format: tlog.FormatJSON, Path: path})
+	r.NoError(err)
+
+	l.Logger().With("component", "x").Error("error message")
+
+	r.NoError(l.Close())
+
+	var record map[string]any
+
+	r.NoError(json.Unmarshal([]byte(readLogLines(t, path)[0]), &record))
+	r.Equal("x", record["component"])
+	r.Contains(record["stacktrace"], "Test_Logger_Stacktrace_DerivedJSON")
+}
+
+func Test_Logger_StacktraceAttr_With(t *testing.T) {
+	t.Parallel()
+
+	r := require.New(t)
+
+	path := filepath.Join(t.TempDir(), "app.log")
+
+	l, err := tlog.New(tlog.Opts{Path: path})
+	r.NoError(err)
+
+	forced := l.Logger().With(tlog.Stacktrace(true), "component", "forced")
+	suppressed := forced.WithGroup("req").With(tlog.Stacktrace(false))
+
+	forced.Info("info message")
+	suppressed.Error("error message")
+	// A record attribute overrides the derived logger.
+	suppressed.Error("error message", tlog.Stacktrace(true))
+	// The parent logger is not affected.
+	l.Logger().Info("info message")
+
+	r.NoError(l.Close())
+
+	lines := readLogLines(t, path)
+	r.Len(lines, 4)
+	r.Contains(lines[0], "component=forced stacktrace=")
+	r.NotContains(lines[1], "stacktrace=")
+	r.Contains(lines[2], "component=forced stacktrace=")
+	r.NotContains(lines[2], "req.stacktrace=")
+	r.NotContains(lines[3], "stacktrace=")
+
+	for _, line := range lines {
+		r.NotContains(line, "stacktrace=true")
+		r.NotContains(line, "stacktrace=false")
+	}
+}
@@ -0,0 +1,30 @@
+package tlog
+
+import (
+	"context"
+	"log/slog"
+)
+
+// Trace logs at LevelTraceSlog with the given logger, e.g. one derived
+// with With. The source of the record is the caller of Trace.
+func Trace(logger *slog.Logger, msg string, args ...any) {
+	logAt(context.Background(), logger, LevelTraceSlog, msg, args...)
+}
+
+// TraceContext logs at LevelTraceSlog with the given logger and context.
+// The source of the record is the caller of TraceContext.
+func TraceContext(ctx context.Context, logger *slog.Logger, msg string, args ...any) {
+	logAt(ctx, logger, LevelTraceSlog, msg, args...)
+}
+
+// Trace logs at LevelTraceSlog. The source of the record is the caller
+// of Trace.
+func (l *Logger) Trace(msg string, args ...any) {
+	logAt(context.Background(), l.logger, LevelTraceSlog, msg, args...)
+}
+
+// TraceContext logs at LevelTraceSlog with the given context. The source
+// of the record is the caller of TraceContext.
+func (l *Logger) TraceContext(ctx context.Context, msg string, args ...any) {
+	logAt(ctx, l.logger, LevelTraceSlog, msg, args...)
+}
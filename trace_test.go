@@ -0,0 +1,134 @@
+package tlog_test
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/tarantool/go-tlog"
+)
+
+// line returns the line of its caller.
+func line() int {
+	_, _, line, _ := runtime.Caller(1)
+	return line
+}
+
+func Test_Logger_Trace_Text(t *testing.T) {
+	t.Parallel()
+
+	r := require.New(t)
+
+	path := filepath.Join(t.TempDir(), "app.log")
+
+	l, err := tlog.New(tlog.Opts{Level: tlog.LevelTrace, Path: path})
+	r.NoError(err)
+
+	l.Trace("trace message", "key", "value")
+	traceLine := line() - 1
+
+	tlog.TraceContext(context.Background(), l.Logger().With("component", "x"), "derived message")
+	derivedLine := line() - 1
+
+	r.NoError(l.Close())
+
+	logs, err := os.ReadFile(path)
+	r.NoError(err)
+
+	lines := strings.Split(strings.TrimSuffix(string(logs), "\n"), "\n")
+	r.Len(lines, 2)
+
+	r.Contains(lines[0], " TRACE ")
+	r.Contains(lines[0], "trace_test.go:"+strconv.Itoa(traceLine)+` "trace message" key=value`)
+	r.NotContains(lines[0], "stacktrace=")
+
+	r.Contains(lines[1], " TRACE ")
+	r.Contains(lines[1], "trace_test.go:"+strconv.Itoa(derivedLine)+` "derived message" component=x`)
+}
+
+func Test_Logger_Trace_JSON(t *testing.T) {
+	t.Parallel()
+
+	r := require.New(t)
+
+	path := filepath.Join(t.TempDir(), "app.log")
+
+	l, err := tlog.New(tlog.Opts{Level: tlog.LevelTrace, Format: tlog.FormatJSON, Path: path})
+	r.NoError(err)
+
+	tlog.Trace(l.Logger(), "trace message")
+	traceLine := line() - 1
+
+	l.Logger().Log(context.Background(), tlog.LevelTraceSlog, "log message")
+
+	r.NoError(l.Close())
+
+	logs, err := os.ReadFile(path)
+	r.NoError(err)
+
+	decoder := json.NewDecoder(strings.NewReader(string(logs)))
+
+	for _, msg := range []string{"trace message", "log message"} {
+		var record struct {
+			Level  string      `json:"level"`
+			Msg    string      `json:"msg"`
+			Source slog.Source `json:"source"`
+		}
+
+		r.NoError(decoder.Decode(&record))
+		r.Equal("TRACE", record.Level)
+		r.Equal(msg, record.Msg)
+		r.Equal("trace_test.go", filepath.Base(record.Source.File))
+
+		if msg == "trace message" {
+			r.Equal(traceLine, record.Source.Line)
+		}
+	}
+}
+
+func Test_Logger_Trace_Suppressed(t *testing.T) {
+	t.Parallel()
+
+	r := require.New(t)
+
+	dir := t.TempDir()
+	debugPath := filepath.Join(dir, "debug.log")
+	tracePath := filepath.Join(dir, "trace.log")
+
+	l, err := tlog.New(tlog.Opts{
+		Level: tlog.LevelDebug,
+		Path:  debugPath + "," + tracePath + ":trace",
+	})
+	r.NoError(err)
+
+	r.True(l.Logger().Enabled(context.Background(), tlog.LevelTraceSlog))
+
+	l.Trace("trace message")
+	l.Logger().Debug("debug message")
+
+	r.NoError(l.Close())
+
+	debugLogs, err := os.ReadFile(debugPath)
+	r.NoError(err)
+	r.NotContains(string(debugLogs), "trace message")
+	r.Contains(string(debugLogs), "debug message")
+
+	traceLogs, err := os.ReadFile(tracePath)
+	r.NoError(err)
+	r.Contains(string(traceLogs), "TRACE")
+	r.Contains(string(traceLogs), "trace message")
+	r.Contains(string(traceLogs), "debug message")
+
+	l, err = tlog.New(tlog.Opts{Level: tlog.LevelDebug, Path: debugPath})
+	r.NoError(err)
+	r.False(l.Logger().Enabled(context.Background(), tlog.LevelTraceSlog))
+	r.NoError(l.Close())
+}